@@ -0,0 +1,245 @@
+// Package bench measures how well a strategy plays by playing many games with it in parallel, summarising
+// the scores, tiles reached and speed in a Report that can be written as a table, CSV or JSON.
+package bench
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/solver"
+)
+
+// Milestones are the tiles whose reach rates are reported
+var Milestones = []uint32{2048, 4096, 8192}
+
+// NewStrategy builds the strategy that plays the game with the given seed. Strategies that are not safe for
+// concurrent use must be built anew for every game.
+type NewStrategy func(seed int64) solver.Strategy
+
+// GameResult is the outcome of a single game
+type GameResult struct {
+	Seed        int64         `json:"seed"`
+	Score       uint64        `json:"score"`
+	HighestTile uint32        `json:"highest_tile"`
+	Moves       int           `json:"moves"`
+	Duration    time.Duration `json:"duration_ns"`
+}
+
+// Summary of every game played
+type Summary struct {
+	Games        int     `json:"games"`
+	MeanScore    float64 `json:"mean_score"`
+	StdDevScore  float64 `json:"stddev_score"`
+	MinScore     uint64  `json:"min_score"`
+	P10Score     uint64  `json:"p10_score"`
+	P25Score     uint64  `json:"p25_score"`
+	MedianScore  uint64  `json:"median_score"`
+	P75Score     uint64  `json:"p75_score"`
+	P90Score     uint64  `json:"p90_score"`
+	MaxScore     uint64  `json:"max_score"`
+	AverageMoves float64 `json:"average_moves"`
+	// Reached maps each of the Milestones to the fraction of games with a tile at least as large
+	Reached map[uint32]float64 `json:"reached"`
+	// MovesPerSecond is the number of moves made by all of the workers together each second
+	MovesPerSecond float64 `json:"moves_per_second"`
+}
+
+// Report of a benchmark, with the games ordered by seed
+type Report struct {
+	Strategy string        `json:"strategy"`
+	Workers  int           `json:"workers"`
+	Elapsed  time.Duration `json:"elapsed_ns"`
+	Summary  Summary       `json:"summary"`
+	Games    []GameResult  `json:"games"`
+}
+
+// Run plays the given number of games with the strategy, the nth game seeded with the first seed plus n.
+// Games still being played when ctx is done are abandoned and the report covers the games that finished,
+// along with ctx's error.
+func Run(ctx context.Context, strategy string, newStrategy NewStrategy, games int, options ...Option) (Report, error) {
+	b := benchmark{
+		workers: runtime.GOMAXPROCS(0),
+		seed:    time.Now().UnixNano(),
+	}
+	for _, option := range options {
+		option.apply(&b)
+	}
+
+	var (
+		started = time.Now()
+		seeds   = make(chan int64)
+		results []GameResult
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < b.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for seed := range seeds {
+				if result, ok := b.play(ctx, seed, newStrategy(seed)); ok {
+					mu.Lock()
+					results = append(results, result)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := 0; i < games && ctx.Err() == nil; i++ {
+		select {
+		case seeds <- b.seed + int64(i):
+		case <-ctx.Done():
+		}
+	}
+	close(seeds)
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Seed < results[j].Seed })
+	report := Report{
+		Strategy: strategy,
+		Workers:  b.workers,
+		Elapsed:  time.Since(started),
+		Games:    results,
+	}
+	report.Summary = summarise(results, report.Elapsed)
+	return report, ctx.Err()
+}
+
+type benchmark struct {
+	workers     int
+	seed        int64
+	gameOptions []game.Option
+}
+
+// play plays a game until no moves remain. False is returned if ctx was done first.
+func (b *benchmark) play(ctx context.Context, seed int64, strategy solver.Strategy) (GameResult, bool) {
+	started := time.Now()
+	gc := game.NewController(append(append([]game.Option{}, b.gameOptions...), game.WithSeed(seed), game.WithHistoryDepth(0))...)
+	for !gc.Lost() {
+		if ctx.Err() != nil {
+			return GameResult{}, false
+		}
+		direction, ok := strategy.NextMove(gc.GetCells())
+		if !ok || !gc.Shift(direction) {
+			break
+		}
+	}
+	return GameResult{
+		Seed:        seed,
+		Score:       gc.GetScore(),
+		HighestTile: gc.GetCells().MaxValue(),
+		Moves:       gc.GetMoveCount(),
+		Duration:    time.Since(started),
+	}, true
+}
+
+func summarise(results []GameResult, elapsed time.Duration) Summary {
+	summary := Summary{Games: len(results), Reached: map[uint32]float64{}}
+	for _, milestone := range Milestones {
+		summary.Reached[milestone] = 0
+	}
+	if len(results) == 0 {
+		return summary
+	}
+
+	scores := make([]uint64, len(results))
+	var totalScore float64
+	var totalMoves int
+	for i, r := range results {
+		scores[i] = r.Score
+		totalScore += float64(r.Score)
+		totalMoves += r.Moves
+		for _, milestone := range Milestones {
+			if r.HighestTile >= milestone {
+				summary.Reached[milestone]++
+			}
+		}
+	}
+	games := float64(len(results))
+	for _, milestone := range Milestones {
+		summary.Reached[milestone] /= games
+	}
+	summary.MeanScore = totalScore / games
+	var variance float64
+	for _, score := range scores {
+		variance += (float64(score) - summary.MeanScore) * (float64(score) - summary.MeanScore)
+	}
+	summary.StdDevScore = math.Sqrt(variance / games)
+
+	sort.Slice(scores, func(i, j int) bool { return scores[i] < scores[j] })
+	summary.MinScore = scores[0]
+	summary.P10Score = percentile(scores, 10)
+	summary.P25Score = percentile(scores, 25)
+	summary.MedianScore = percentile(scores, 50)
+	summary.P75Score = percentile(scores, 75)
+	summary.P90Score = percentile(scores, 90)
+	summary.MaxScore = scores[len(scores)-1]
+	summary.AverageMoves = float64(totalMoves) / games
+	if elapsed > 0 {
+		summary.MovesPerSecond = float64(totalMoves) / elapsed.Seconds()
+	}
+	return summary
+}
+
+// percentile returns the nearest rank percentile of the sorted scores
+func percentile(sorted []uint64, p int) uint64 {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Option configures a benchmark
+type Option interface {
+	apply(b *benchmark)
+}
+
+// WithWorkers sets the number of games played at once, by default one for each CPU
+func WithWorkers(workers int) Option {
+	return workersOption{workers: workers}
+}
+
+type workersOption struct {
+	workers int
+}
+
+func (o workersOption) apply(b *benchmark) {
+	if o.workers < 1 {
+		panic("WithWorkers: workers must be at least 1")
+	}
+	b.workers = o.workers
+}
+
+// WithSeed sets the seed of the first game. By default a time based seed is used, the seeds are reported so
+// any game can be played again.
+func WithSeed(seed int64) Option {
+	return seedOption{seed: seed}
+}
+
+type seedOption struct {
+	seed int64
+}
+
+func (o seedOption) apply(b *benchmark) {
+	b.seed = o.seed
+}
+
+// WithGameOptions sets the options every game is built with, the seed of each game is always set by the
+// benchmark
+func WithGameOptions(options ...game.Option) Option {
+	return gameOptionsOption{options: options}
+}
+
+type gameOptionsOption struct {
+	options []game.Option
+}
+
+func (o gameOptionsOption) apply(b *benchmark) {
+	b.gameOptions = append(b.gameOptions, o.options...)
+}
@@ -0,0 +1,115 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/solver"
+)
+
+func newRandom(seed int64) solver.Strategy { return solver.NewRandom(seed) }
+
+func TestRun(t *testing.T) {
+	report, err := Run(context.Background(), "random", newRandom, 20, WithSeed(100), WithWorkers(4))
+	equal(t, nil, err)
+	equal(t, "random", report.Strategy)
+	equal(t, 4, report.Workers)
+	equal(t, 20, len(report.Games))
+	for i, g := range report.Games {
+		equal(t, int64(100+i), g.Seed)
+		equal(t, true, g.Moves > 0 && g.Score > 0 && g.HighestTile >= 16)
+	}
+
+	s := report.Summary
+	equal(t, 20, s.Games)
+	equal(t, true, s.MinScore <= s.P10Score && s.P10Score <= s.P25Score && s.P25Score <= s.MedianScore)
+	equal(t, true, s.MedianScore <= s.P75Score && s.P75Score <= s.P90Score && s.P90Score <= s.MaxScore)
+	equal(t, true, float64(s.MinScore) <= s.MeanScore && s.MeanScore <= float64(s.MaxScore))
+	equal(t, map[uint32]float64{2048: 0, 4096: 0, 8192: 0}, s.Reached)
+	equal(t, true, s.AverageMoves > 0 && s.MovesPerSecond > 0)
+
+	// every game is played again exactly with the same seeds, whatever the number of workers
+	again, err := Run(context.Background(), "random", newRandom, 20, WithSeed(100), WithWorkers(1))
+	equal(t, nil, err)
+	for i := range report.Games {
+		report.Games[i].Duration, again.Games[i].Duration = 0, 0
+	}
+	equal(t, report.Games, again.Games)
+}
+
+func TestRun_gameOptions(t *testing.T) {
+	report, err := Run(context.Background(), "corner", func(int64) solver.Strategy { return solver.NewCorner() }, 3,
+		WithSeed(1), WithGameOptions(game.WithSize(2, 2), game.WithSeed(99)))
+	equal(t, nil, err)
+	equal(t, []int64{1, 2, 3}, []int64{report.Games[0].Seed, report.Games[1].Seed, report.Games[2].Seed})
+	for _, g := range report.Games {
+		equal(t, true, g.HighestTile <= 32)
+	}
+}
+
+func TestRun_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := Run(ctx, "random", newRandom, 1000, WithSeed(1))
+	equal(t, context.Canceled, err)
+	equal(t, true, len(report.Games) < 1000)
+	equal(t, len(report.Games), report.Summary.Games)
+}
+
+func TestSummarise(t *testing.T) {
+	var results []GameResult
+	for i := 1; i <= 10; i++ {
+		results = append(results, GameResult{Seed: int64(i), Score: uint64(i * 100), HighestTile: uint32(1024 << uint(i%4)), Moves: 10})
+	}
+	s := summarise(results, 0)
+	equal(t, 550.0, s.MeanScore)
+	equal(t, uint64(100), s.MinScore)
+	equal(t, uint64(100), s.P10Score)
+	equal(t, uint64(300), s.P25Score)
+	equal(t, uint64(500), s.MedianScore)
+	equal(t, uint64(800), s.P75Score)
+	equal(t, uint64(900), s.P90Score)
+	equal(t, uint64(1000), s.MaxScore)
+	equal(t, 10.0, s.AverageMoves)
+	// tiles of 1024, 2048, 4096 and 8192 in turn
+	equal(t, map[uint32]float64{2048: 0.8, 4096: 0.5, 8192: 0.2}, s.Reached)
+}
+
+func TestReport_Write(t *testing.T) {
+	report, err := Run(context.Background(), "greedy", func(int64) solver.Strategy { return solver.NewGreedy() }, 3, WithSeed(5))
+	equal(t, nil, err)
+
+	var table bytes.Buffer
+	equal(t, nil, report.WriteTable(&table))
+	equal(t, true, strings.HasPrefix(table.String(), "strategy  greedy\n"))
+	equal(t, true, strings.Contains(table.String(), "reached   2048 0.0%  4096 0.0%  8192 0.0%\n"))
+	equal(t, 11, strings.Count(table.String(), "\n"))
+
+	var csvOut bytes.Buffer
+	equal(t, nil, report.WriteCSV(&csvOut))
+	records, err := csv.NewReader(&csvOut).ReadAll()
+	equal(t, nil, err)
+	equal(t, 4, len(records))
+	equal(t, []string{"strategy", "seed", "score", "highest_tile", "moves", "duration_ms"}, records[0])
+	equal(t, []string{"greedy", "5"}, records[1][:2])
+
+	var jsonOut bytes.Buffer
+	equal(t, nil, report.WriteJSON(&jsonOut))
+	var decoded Report
+	equal(t, nil, json.Unmarshal(jsonOut.Bytes(), &decoded))
+	equal(t, report.Games, decoded.Games)
+	equal(t, report.Summary, decoded.Summary)
+}
+
+func equal(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,61 @@
+package bench
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteTable writes the summary followed by a row for every game, aligned for reading in a terminal
+func (r Report) WriteTable(w io.Writer) error {
+	s := r.Summary
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "strategy\t%s\n", r.Strategy)
+	fmt.Fprintf(tw, "games\t%d in %s\n", s.Games, r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(tw, "workers\t%d\n", r.Workers)
+	fmt.Fprintf(tw, "score\tmean %.0f ± %.0f  min %d  p10 %d  p25 %d  median %d  p75 %d  p90 %d  max %d\n",
+		s.MeanScore, s.StdDevScore, s.MinScore, s.P10Score, s.P25Score, s.MedianScore, s.P75Score, s.P90Score, s.MaxScore)
+	reached := make([]string, len(Milestones))
+	for i, milestone := range Milestones {
+		reached[i] = fmt.Sprintf("%d %.1f%%", milestone, 100*s.Reached[milestone])
+	}
+	fmt.Fprintf(tw, "reached\t%s\n", strings.Join(reached, "  "))
+	fmt.Fprintf(tw, "moves\t%.1f per game, %.0f per second\n", s.AverageMoves, s.MovesPerSecond)
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "seed\tscore\thighest tile\tmoves\tduration")
+	for _, g := range r.Games {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%.1fms\n", g.Seed, g.Score, g.HighestTile, g.Moves, g.Duration.Seconds()*1000)
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes a header and a row for every game, the strategy repeated on each row so the output of
+// several benchmarks can be joined
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"strategy", "seed", "score", "highest_tile", "moves", "duration_ms"})
+	for _, g := range r.Games {
+		cw.Write([]string{
+			r.Strategy,
+			strconv.FormatInt(g.Seed, 10),
+			strconv.FormatUint(g.Score, 10),
+			strconv.FormatUint(uint64(g.HighestTile), 10),
+			strconv.Itoa(g.Moves),
+			strconv.FormatFloat(g.Duration.Seconds()*1000, 'f', 3, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the whole report as indented JSON
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"github.com/brandenc40/2048/bench"
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/ntuple"
+	"github.com/brandenc40/2048/solver"
+)
+
+func runBench(args []string) error {
+	var (
+		strategy string
+		games    int
+		workers  int
+		format   string
+		depth    int
+		rollouts int
+		budget   time.Duration
+		weights  string
+	)
+	flags := newFlagSet("bench", "")
+	gameFlags := addGameFlags(flags)
+	flags.StringVar(&strategy, "strategy", "expectimax", `Strategy to play with: "random", "greedy", "corner", "expectimax", "montecarlo" or "ntuple".`)
+	flags.IntVar(&games, "games", 100, "Number of games to play. With -seed the games use that seed and the ones after it.")
+	flags.IntVar(&workers, "workers", runtime.GOMAXPROCS(0), "Number of games played at once.")
+	flags.StringVar(&format, "format", "table", `Format of the report, "table", "csv" or "json". CSV has a row for each game.`)
+	flags.IntVar(&depth, "depth", 2, "Number of moves the expectimax strategy searches ahead.")
+	flags.IntVar(&rollouts, "rollouts", 100, "Number of random games the montecarlo strategy plays after each move.")
+	flags.DurationVar(&budget, "rollout-budget", 0, "Time the montecarlo strategy may spend on each move, playing fewer games if it runs out. Unlimited if 0.")
+	flags.StringVar(&weights, "weights", defaultWeightsPath(), "File the ntuple strategy loads its network from, trained with the train command.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := checkNoArgs(flags); err != nil {
+		return err
+	}
+
+	if games < 1 || workers < 1 {
+		return errors.New("-games and -workers must be at least 1")
+	}
+	var write func(bench.Report) error
+	switch format {
+	case "table":
+		write = func(r bench.Report) error { return r.WriteTable(os.Stdout) }
+	case "csv":
+		write = func(r bench.Report) error { return r.WriteCSV(os.Stdout) }
+	case "json":
+		write = func(r bench.Report) error { return r.WriteJSON(os.Stdout) }
+	default:
+		return fmt.Errorf("unknown format %q, expected \"table\", \"csv\" or \"json\"", format)
+	}
+	gameOptions, err := gameFlags.options()
+	if err != nil {
+		return err
+	}
+	spawn, err := gameFlags.spawnPolicy()
+	if err != nil {
+		return err
+	}
+	if strategy == "ntuple" {
+		if err := checkNTupleSize(gameFlags.rows, gameFlags.cols); err != nil {
+			return err
+		}
+	}
+	newStrategy, err := benchStrategy(strategy, spawn, uint32(gameFlags.winTarget), depth, rollouts, budget, weights)
+	if err != nil {
+		return err
+	}
+
+	options := []bench.Option{bench.WithWorkers(workers), bench.WithGameOptions(gameOptions...)}
+	if isFlagSet(flags, "seed") {
+		options = append(options, bench.WithSeed(gameFlags.seed))
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, err := bench.Run(ctx, strategy, newStrategy, games, options...)
+	// the games finished before an interrupt are still reported
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return write(report)
+}
+
+// benchStrategy returns the builder of the named strategy. Strategies that keep state between moves are
+// built for every game, the others are shared.
+func benchStrategy(name string, spawn game.SpawnPolicy, winTarget uint32, depth, rollouts int, budget time.Duration, weights string) (bench.NewStrategy, error) {
+	switch name {
+	case "random":
+		return func(seed int64) solver.Strategy { return solver.NewRandom(seed) }, nil
+	case "greedy":
+		return func(int64) solver.Strategy { return solver.NewGreedy() }, nil
+	case "corner":
+		return func(int64) solver.Strategy { return solver.NewCorner() }, nil
+	case "expectimax":
+		if depth < 1 {
+			return nil, errors.New("-depth must be at least 1")
+		}
+		expectimax := solver.NewExpectimax(solver.WithDepth(depth), solver.WithSpawnPolicy(spawn))
+		return func(int64) solver.Strategy { return expectimax }, nil
+	case "montecarlo":
+		if rollouts < 1 || budget < 0 {
+			return nil, errors.New("-rollouts must be at least 1 and -rollout-budget must not be negative")
+		}
+		// games are already played in parallel, so each one plays its rollouts on a single goroutine
+		return func(seed int64) solver.Strategy {
+			return solver.NewMonteCarlo(
+				solver.WithSeed(seed),
+				solver.WithRollouts(rollouts),
+				solver.WithTimeBudget(budget),
+				solver.WithWorkers(1),
+				solver.WithRolloutSpawnPolicy(spawn),
+				solver.WithWinTarget(winTarget),
+			)
+		}, nil
+	case "ntuple":
+		network, err := ntuple.Load(weights)
+		if err != nil {
+			return nil, err
+		}
+		player := ntuple.NewPlayer(network)
+		return func(int64) solver.Strategy { return player }, nil
+	default:
+		return nil, fmt.Errorf(`unknown strategy %q, expected "random", "greedy", "corner", "expectimax", "montecarlo" or "ntuple"`, name)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/solver"
+)
+
+// gameFlags configure the game board, shared by every command that starts a new game
+type gameFlags struct {
+	flags      *flag.FlagSet
+	rows, cols int
+	seed       int64
+	undoLimit  int
+	winTarget  uint
+	spawn      string
+	adversary  bool
+}
+
+func addGameFlags(flags *flag.FlagSet) *gameFlags {
+	f := &gameFlags{flags: flags}
+	flags.IntVar(&f.rows, "rows", 4, "Number of rows on the game board.")
+	flags.IntVar(&f.cols, "cols", 4, "Number of columns on the game board.")
+	flags.Int64Var(&f.seed, "seed", 0, "Seed for the random tile placement, games with the same seed play out identically. Random if not set.")
+	flags.IntVar(&f.undoLimit, "undo-limit", -1, "Maximum number of undos per game. Unlimited if negative.")
+	flags.UintVar(&f.winTarget, "target", 2048, "Tile value needed to win the game, must be a power of two.")
+	flags.StringVar(&f.spawn, "spawn", "classic", "Odds and number of the tiles added after each move. Options are "+quoteAll(game.SpawnPresets())+".")
+	flags.BoolVar(&f.adversary, "adversary", false, "Let the solver place every new tile wherever it hurts the most, instead of at random.")
+	return f
+}
+
+// options returns the game options set by the flags, call it once the flags have been parsed. Values the
+// options would reject are returned as an error after the usage message is printed.
+func (f *gameFlags) options() ([]game.Option, error) {
+	if err := f.validate(); err != nil {
+		f.flags.Usage()
+		return nil, err
+	}
+	spawn, err := f.spawnPolicy()
+	if err != nil {
+		return nil, err
+	}
+	options := []game.Option{
+		game.WithSize(f.rows, f.cols),
+		game.WithWinTarget(uint32(f.winTarget)),
+		game.WithSpawnPolicy(spawn),
+	}
+	if isFlagSet(f.flags, "seed") {
+		options = append(options, game.WithSeed(f.seed))
+	}
+	if f.undoLimit >= 0 {
+		options = append(options, game.WithUndoLimit(f.undoLimit))
+	}
+	if f.adversary {
+		options = append(options, game.WithAdversary(solver.NewAdversary(solver.WithSpawnPolicy(spawn))))
+	}
+	return options, nil
+}
+
+// validate checks the flag values that the game options panic on
+func (f *gameFlags) validate() error {
+	if f.rows < 2 || f.cols < 2 {
+		return errors.New("-rows and -cols must be at least 2")
+	}
+	if f.winTarget < 4 || f.winTarget > math.MaxUint32 || f.winTarget&(f.winTarget-1) != 0 {
+		return fmt.Errorf("-target must be a power of two from 4 to %d", uint64(1)<<31)
+	}
+	if _, err := f.spawnPolicy(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkNTupleSize returns an error unless the board is 4x4, the only size n-tuple networks can play
+func checkNTupleSize(rows, cols int) error {
+	if rows != 4 || cols != 4 {
+		return fmt.Errorf("the ntuple player only plays 4x4 boards, not %dx%d", rows, cols)
+	}
+	return nil
+}
+
+// spawnPolicy returns the spawn policy preset named by the -spawn flag
+func (f *gameFlags) spawnPolicy() (game.SpawnPolicy, error) {
+	spawn, ok := game.SpawnPreset(f.spawn)
+	if !ok {
+		return game.SpawnPolicy{}, fmt.Errorf("unknown spawn policy %q, options are %s", f.spawn, quoteAll(game.SpawnPresets()))
+	}
+	return spawn, nil
+}
+
+// quoteAll quotes each name and joins them into a list such as "a", "b", and "c"
+func quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	if len(quoted) < 2 {
+		return strings.Join(quoted, "")
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + ", and " + quoted[len(quoted)-1]
+}
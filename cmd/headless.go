@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/headless"
+)
+
+func runHeadless(args []string) error {
+	var format string
+	flags := newFlagSet("headless", "")
+	gameFlags := addGameFlags(flags)
+	flags.StringVar(&format, "format", "text", `Format the board is written in after each command, "text" or "json".`)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := checkNoArgs(flags); err != nil {
+		return err
+	}
+
+	var formatOption headless.Option
+	switch format {
+	case "text":
+		formatOption = headless.WithFormat(headless.FormatText)
+	case "json":
+		formatOption = headless.WithFormat(headless.FormatJSON)
+	default:
+		return fmt.Errorf("unknown format %q, expected \"text\" or \"json\"", format)
+	}
+	gameOptions, err := gameFlags.options()
+	if err != nil {
+		return err
+	}
+	return headless.Run(game.NewController(gameOptions...), os.Stdin, os.Stdout, formatOption)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/solver"
+	"github.com/brandenc40/2048/terminalui"
+)
+
+// hintMaxDepth bounds the iterative deepening of hint searches, the time budget usually ends them sooner
+const hintMaxDepth = 6
+
+// expectimaxAdvisor suggests the move chosen by an expectimax search deepened until the time budget runs out
+type expectimaxAdvisor struct {
+	expectimax *solver.Expectimax
+}
+
+var _ terminalui.Advisor = expectimaxAdvisor{}
+
+// newExpectimaxAdvisor builds an advisor searching with the options, which should match the game's spawn
+// policy and max cell
+func newExpectimaxAdvisor(options ...solver.ExpectimaxOption) expectimaxAdvisor {
+	options = append([]solver.ExpectimaxOption{solver.WithDepth(hintMaxDepth)}, options...)
+	return expectimaxAdvisor{expectimax: solver.NewExpectimax(options...)}
+}
+
+func (a expectimaxAdvisor) Advise(ctx context.Context, cells game.Cells) (terminalui.Hint, bool) {
+	values, depth := a.expectimax.Search(ctx, cells)
+	if len(values) == 0 {
+		return terminalui.Hint{}, false
+	}
+	best := values[0]
+	rationale := fmt.Sprintf("+%d points, searched %d moves ahead", best.Score, depth)
+	if len(values) > 1 {
+		rationale += " (next best: " + solver.DirectionName(values[1].Direction) + ")"
+	}
+	return terminalui.Hint{Direction: best.Direction, Rationale: rationale}, true
+}
@@ -2,35 +2,84 @@ package main
 
 import (
 	"flag"
-
-	"github.com/brandenc40/2048/game"
-	"github.com/brandenc40/2048/terminalui"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
 )
 
+// command is a subcommand of the 2048 binary, run with the arguments that follow its name
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	// assigned in init as the commands refer back to the map for their usage messages
+	commands = map[string]command{
+		"play":     {usage: "Play 2048 in the terminal, the default when no command is given.", run: runPlay},
+		"replay":   {usage: "Watch a recorded game.", run: runReplay},
+		"headless": {usage: "Play without a terminal, reading moves from stdin and writing the board to stdout.", run: runHeadless},
+		"server":   {usage: "Host games over an HTTP JSON API.", run: runServer},
+		"ssh":      {usage: "Host games over SSH, each session playing in its own terminal.", run: runSSH},
+		"web":      {usage: "Play in the browser, served over HTTP and a WebSocket.", run: runWeb},
+		"bench":    {usage: "Play many games with a strategy and report how well it scored.", run: runBench},
+		"train":    {usage: "Train an n-tuple network by self-play for the ntuple autoplayer.", run: runTrain},
+	}
+}
+
 func main() {
-	var output string
-	flag.StringVar(&output, "output", "rgb",
-		`Output mode use for displaying colors in the terminal. Options are "rgb", "256", and "normal". 
-If you are not able to see the game board, your terminal most likely does not support "rgb". 
-In that case please use "256", or "normal".`)
-
-	flag.Parse()
-
-	terminalui.Run(
-		game.NewController(),
-		parseOutModeOption(output),
-	)
+	name, args := "play", os.Args[1:]
+	if len(args) > 0 {
+		if _, ok := commands[args[0]]; ok {
+			name, args = args[0], args[1:]
+		}
+	}
+	err := commands[name].run(args)
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// newFlagSet builds the flags of a subcommand, listing every subcommand in its usage message
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Usage: 2048 %s [flags] %s\n\n%s\n\nFlags:\n", name, args, commands[name].usage)
+		fs.PrintDefaults()
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintln(out, "\nCommands:")
+		for _, name := range names {
+			fmt.Fprintf(out, "  %-8s %s\n", name, commands[name].usage)
+		}
+	}
+	return fs
+}
+
+func isFlagSet(fs *flag.FlagSet, name string) (isSet bool) {
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			isSet = true
+		}
+	})
+	return
 }
 
-func parseOutModeOption(output string) terminalui.Option {
-	switch output {
-	case "normal":
-		return terminalui.WithOutputMode(terminalui.OutputModeNormal)
-	case "256":
-		return terminalui.WithOutputMode(terminalui.OutputMode256)
-	case "rgb":
-		return terminalui.WithOutputMode(terminalui.OutputModeRGB)
-	default:
-		return terminalui.WithOutputMode(terminalui.OutputModeRGB)
+// checkNoArgs returns an error if arguments were given after the flags of a command that takes none
+func checkNoArgs(fs *flag.FlagSet) error {
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
 	}
+	return nil
 }
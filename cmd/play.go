@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/ntuple"
+	"github.com/brandenc40/2048/replay"
+	"github.com/brandenc40/2048/solver"
+	"github.com/brandenc40/2048/stats"
+	"github.com/brandenc40/2048/terminalui"
+)
+
+func runPlay(args []string) error {
+	var (
+		output    string
+		screen    string
+		animation time.Duration
+		endless   bool
+		savePath  string
+		resume    bool
+		statsPath string
+		replayDir string
+		autoplay  bool
+		player    string
+		depth     int
+		rollouts  int
+		budget    time.Duration
+		weights   string
+		delay     time.Duration
+		hintTime  time.Duration
+		keysPath  string
+	)
+	flags := newFlagSet("play", "")
+	gameFlags := addGameFlags(flags)
+	flags.StringVar(&output, "output", "rgb",
+		`Output mode use for displaying colors in the terminal. Options are "rgb", "256", and "normal". 
+If you are not able to see the game board, your terminal most likely does not support "rgb". 
+In that case please use "256", or "normal".`)
+	flags.StringVar(&screen, "screen", "termbox", screenUsage)
+	flags.DurationVar(&animation, "animation", 120*time.Millisecond, "Duration of the tile animations after each move. Disabled if 0.")
+	flags.BoolVar(&endless, "endless", false, "Offer to keep playing after reaching the target tile.")
+	flags.StringVar(&savePath, "save", filepath.Join(dataDir(), "save.json"), "File the game is saved to on exit.")
+	flags.BoolVar(&resume, "resume", false, "Resume the game saved on the last exit.")
+	flags.StringVar(&statsPath, "stats", filepath.Join(dataDir(), "stats.json"), "File your game statistics are kept in.")
+	flags.StringVar(&replayDir, "replays", filepath.Join(dataDir(), "replays"), "Directory every game is recorded to. Recording is disabled if empty.")
+	flags.BoolVar(&autoplay, "autoplay", false, "Let a solver play the game, chosen with -player.")
+	flags.StringVar(&player, "player", "expectimax", `Solver that plays when autoplay is on, "expectimax", "montecarlo" or "ntuple".`)
+	flags.IntVar(&depth, "depth", 2, "Number of moves the expectimax solver searches ahead.")
+	flags.IntVar(&rollouts, "rollouts", 200, "Number of random games the montecarlo solver plays after each move.")
+	flags.DurationVar(&budget, "rollout-budget", 0, "Time the montecarlo solver may spend on each move, playing fewer games if it runs out. Unlimited if 0.")
+	flags.StringVar(&weights, "weights", defaultWeightsPath(), "File the ntuple solver loads its network from, trained with the train command.")
+	flags.DurationVar(&delay, "autoplay-delay", 150*time.Millisecond, "Delay between moves made by autoplay.")
+	flags.DurationVar(&hintTime, "hint-time", 500*time.Millisecond, "Time the solver may spend searching for a hint.")
+	flags.StringVar(&keysPath, "keys", filepath.Join(dataDir(), "keys.json"), "File your key bindings are read from, the default bindings are used if it does not exist.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := checkNoArgs(flags); err != nil {
+		return err
+	}
+	// checked before the terminal is taken over, the options panic on these values
+	if autoplay && delay <= 0 {
+		return errors.New("-autoplay-delay must be positive")
+	}
+	if autoplay && player == "expectimax" && depth < 1 {
+		return errors.New("-depth must be at least 1")
+	}
+	if autoplay && player == "montecarlo" && (rollouts < 1 || budget < 0) {
+		return errors.New("-rollouts must be at least 1 and -rollout-budget must not be negative")
+	}
+
+	screenOption, err := parseScreenOption(screen)
+	if err != nil {
+		return err
+	}
+	keys, err := terminalui.LoadKeyMap(keysPath)
+	if err != nil {
+		return err
+	}
+	uiOptions := []terminalui.Option{
+		screenOption,
+		parseOutModeOption(output),
+		terminalui.WithAnimationDuration(animation),
+		terminalui.WithKeyMap(keys),
+	}
+	if endless {
+		uiOptions = append(uiOptions, terminalui.WithEndless())
+	}
+	store, err := stats.Open(statsPath)
+	if err != nil {
+		return err
+	}
+	uiOptions = append(uiOptions, terminalui.WithStats(store))
+
+	gameOptions, err := gameFlags.options()
+	if err != nil {
+		return err
+	}
+	gc := game.NewController(gameOptions...)
+	if resume {
+		if gc, err = loadGame(savePath, gameOptions...); err != nil {
+			return err
+		}
+	}
+
+	// a resumed game keeps the spawn policy it was saved with, the solver has to expect the same tiles
+	if hintTime > 0 {
+		advisor := newExpectimaxAdvisor(solver.WithSpawnPolicy(gc.SpawnPolicy()), solver.WithMaxCell(gc.MaxCell()))
+		uiOptions = append(uiOptions, terminalui.WithAdvisor(advisor, hintTime))
+	}
+	if autoplay {
+		var strategy solver.Strategy
+		switch player {
+		case "expectimax":
+			strategy = solver.NewExpectimax(
+				solver.WithDepth(depth),
+				solver.WithSpawnPolicy(gc.SpawnPolicy()),
+				solver.WithMaxCell(gc.MaxCell()),
+			)
+		case "montecarlo":
+			strategy = solver.NewMonteCarlo(
+				solver.WithRollouts(rollouts),
+				solver.WithTimeBudget(budget),
+				solver.WithRolloutSpawnPolicy(gc.SpawnPolicy()),
+				solver.WithWinTarget(gc.WinTarget()),
+				solver.WithRolloutMaxCell(gc.MaxCell()),
+			)
+		case "ntuple":
+			// checked on the game rather than the flags, a resumed game keeps the size it was saved with
+			cells := gc.GetCells()
+			if err := checkNTupleSize(cells.Rows(), cells.Cols()); err != nil {
+				return err
+			}
+			network, err := ntuple.Load(weights)
+			if err != nil {
+				return err
+			}
+			strategy = ntuple.NewPlayer(network)
+		default:
+			return fmt.Errorf("unknown player %q, expected \"expectimax\", \"montecarlo\" or \"ntuple\"", player)
+		}
+		uiOptions = append(uiOptions, terminalui.WithAutoplay(strategy, delay))
+	}
+
+	var (
+		archive  = &replayArchive{dir: replayDir}
+		recorder *replay.Recorder
+	)
+	// replays are played back with random spawns, so games against the adversary are not recorded
+	if replayDir != "" && !gameFlags.adversary {
+		recorder = archive.recorder(gc)
+		gc = recorder
+	}
+
+	// the game is still saved if the terminal fails part way through
+	uiErr := terminalui.Run(gc, uiOptions...)
+
+	if recorder != nil {
+		if r, ok := recorder.Replay(); ok && len(r.Events) > 0 {
+			archive.write(r)
+		}
+	}
+	if err := saveGame(savePath, gc); err != nil {
+		return err
+	}
+	if uiErr != nil {
+		return uiErr
+	}
+	return archive.err
+}
+
+const screenUsage = `Library used to draw to the terminal, "termbox" or "tcell". Try "tcell" if the game is not drawn correctly.`
+
+func parseScreenOption(screen string) (terminalui.Option, error) {
+	switch screen {
+	case "termbox":
+		return terminalui.WithScreen(terminalui.NewTermboxScreen()), nil
+	case "tcell":
+		return terminalui.WithScreen(terminalui.NewTcellScreen()), nil
+	default:
+		return nil, fmt.Errorf("unknown screen %q, expected \"termbox\" or \"tcell\"", screen)
+	}
+}
+
+func parseOutModeOption(output string) terminalui.Option {
+	switch output {
+	case "normal":
+		return terminalui.WithOutputMode(terminalui.OutputModeNormal)
+	case "256":
+		return terminalui.WithOutputMode(terminalui.OutputMode256)
+	case "rgb":
+		return terminalui.WithOutputMode(terminalui.OutputModeRGB)
+	default:
+		return terminalui.WithOutputMode(terminalui.OutputModeRGB)
+	}
+}
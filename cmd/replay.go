@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/replay"
+	"github.com/brandenc40/2048/terminalui"
+)
+
+func runReplay(args []string) error {
+	var (
+		output    string
+		screen    string
+		animation time.Duration
+		replayDir string
+		keysPath  string
+	)
+	flags := newFlagSet("replay", "[file]")
+	flags.StringVar(&output, "output", "rgb", `Output mode use for displaying colors in the terminal. Options are "rgb", "256", and "normal".`)
+	flags.StringVar(&screen, "screen", "termbox", screenUsage)
+	flags.DurationVar(&animation, "animation", 120*time.Millisecond, "Duration of the tile animations after each move. Disabled if 0.")
+	flags.StringVar(&replayDir, "replays", filepath.Join(dataDir(), "replays"), "Directory searched for the most recent replay when no file is given.")
+	flags.StringVar(&keysPath, "keys", filepath.Join(dataDir(), "keys.json"), "File your key bindings are read from, the default bindings are used if it does not exist.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return fmt.Errorf("expected a single replay file, got %d", flags.NArg())
+	}
+
+	path := flags.Arg(0)
+	if path == "" {
+		latest, err := latestReplay(replayDir, "*")
+		if err != nil {
+			return err
+		}
+		if latest == "" {
+			return fmt.Errorf("no replays found in %s", replayDir)
+		}
+		path = latest
+	}
+	r, err := readReplay(path)
+	if err != nil {
+		return err
+	}
+	screenOption, err := parseScreenOption(screen)
+	if err != nil {
+		return err
+	}
+	keys, err := terminalui.LoadKeyMap(keysPath)
+	if err != nil {
+		return err
+	}
+	return terminalui.RunReplay(replay.NewPlayer(r),
+		screenOption,
+		parseOutModeOption(output),
+		terminalui.WithAnimationDuration(animation),
+		terminalui.WithKeyMap(keys),
+	)
+}
+
+// replayArchive writes the replay of every game to a directory, keeping the first error for after the game
+type replayArchive struct {
+	dir string
+	err error
+}
+
+// recorder starts recording the game. A resumed game continues its replay from the archive, if it is there.
+func (a *replayArchive) recorder(gc game.Controller) *replay.Recorder {
+	if gc.GetMoveCount() > 0 {
+		if path, err := latestReplay(a.dir, strconv.FormatInt(gc.Seed(), 10)); err == nil && path != "" {
+			if r, err := readReplay(path); err == nil {
+				if recorder, err := replay.ResumeRecorder(gc, r, a.write); err == nil {
+					return recorder
+				}
+			}
+		}
+	}
+	return replay.NewRecorder(gc, a.write)
+}
+
+func (a *replayArchive) write(r replay.Replay) {
+	if err := writeReplay(replayPath(a.dir, r), r); err != nil && a.err == nil {
+		a.err = err
+	}
+}
+
+// replayPath names replay files by the time the game started and its seed, so they sort oldest first and
+// a resumed game can find its replay again
+func replayPath(dir string, r replay.Replay) string {
+	return filepath.Join(dir, r.Started.Format("20060102-150405")+"_"+strconv.FormatInt(r.Seed, 10)+".json")
+}
+
+// latestReplay returns the most recent replay in dir recorded with the seed, which may be "*" to match any
+// seed. An empty path is returned if there is none.
+func latestReplay(dir, seed string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*_"+seed+".json"))
+	if err != nil {
+		return "", fmt.Errorf("finding replays: %w", err)
+	}
+	if len(matches) == 0 {
+		return "", nil
+	}
+	sort.Strings(matches)
+	return matches[len(matches)-1], nil
+}
+
+func readReplay(path string) (replay.Replay, error) {
+	var r replay.Replay
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, fmt.Errorf("no replay found at %s", path)
+	} else if err != nil {
+		return r, fmt.Errorf("reading replay: %w", err)
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("loading replay %s: %w", path, err)
+	}
+	return r, nil
+}
+
+func writeReplay(path string, r replay.Replay) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encoding replay: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating replay directory: %w", err)
+	}
+	// write to a temporary file first so a failed write never corrupts a replay being resumed
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing replay: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("writing replay: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/brandenc40/2048/game"
+)
+
+// dataDir returns the directory used to store saved games and other local data
+func dataDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return filepath.Join(home, ".2048")
+}
+
+// loadGame restores a game saved to the given path
+func loadGame(path string, options ...game.Option) (game.Controller, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no saved game found at %s", path)
+	} else if err != nil {
+		return nil, fmt.Errorf("reading saved game: %w", err)
+	}
+	gc, err := game.LoadController(data, options...)
+	if err != nil {
+		return nil, fmt.Errorf("loading saved game %s: %w", path, err)
+	}
+	return gc, nil
+}
+
+// saveGame writes the game to the given path so it can be resumed later. Games with no moves remaining
+// are not worth resuming, so any existing save is removed instead.
+func saveGame(path string, gc game.Controller) error {
+	if gc.Lost() {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing saved game: %w", err)
+		}
+		return nil
+	}
+	data, err := gc.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("encoding game: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating save directory: %w", err)
+	}
+	// write to a temporary file first so a failed write never corrupts the previous save
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing saved game: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("writing saved game: %w", err)
+	}
+	return nil
+}
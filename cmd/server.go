@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/brandenc40/2048/server"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+func runServer(args []string) error {
+	var (
+		addr        string
+		ttl         time.Duration
+		maxSessions int
+	)
+	flags := newFlagSet("server", "")
+	flags.StringVar(&addr, "addr", ":8080", "Address the server listens on.")
+	flags.DurationVar(&ttl, "ttl", 30*time.Minute, "Time a game is kept after its last request.")
+	flags.IntVar(&maxSessions, "max-games", 10000, "Maximum number of games in progress at once.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := checkNoArgs(flags); err != nil {
+		return err
+	}
+	if ttl <= 0 || maxSessions < 1 {
+		return errors.New("ttl must be positive and max-games at least 1")
+	}
+
+	handler := server.New(server.WithSessionTTL(ttl), server.WithMaxSessions(maxSessions))
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	srv.RegisterOnShutdown(handler.Stop)
+	return serve(srv, addr)
+}
+
+// gracefulServer is a server that can be shut down without interrupting requests in progress, such as an
+// http.Server
+type gracefulServer interface {
+	ListenAndServe() error
+	Shutdown(ctx context.Context) error
+}
+
+// serve runs the server until it fails or the process is interrupted, then shuts it down gracefully
+func serve(srv gracefulServer, addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		fmt.Fprintf(os.Stderr, "listening on %s\n", addr)
+		errs <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
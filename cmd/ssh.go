@@ -0,0 +1,66 @@
+package main
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/brandenc40/2048/solver"
+	"github.com/brandenc40/2048/sshserver"
+	"github.com/brandenc40/2048/terminalui"
+)
+
+func runSSH(args []string) error {
+	var (
+		addr      string
+		hostKey   string
+		statsDir  string
+		output    string
+		animation time.Duration
+		endless   bool
+		hintTime  time.Duration
+	)
+	flags := newFlagSet("ssh", "")
+	gameFlags := addGameFlags(flags)
+	flags.StringVar(&addr, "addr", ":2222", "Address the SSH server listens on.")
+	flags.StringVar(&hostKey, "host-key", filepath.Join(dataDir(), "ssh_host_ed25519_key"), "File the host key is kept in, generated if it does not exist.")
+	flags.StringVar(&statsDir, "stats-dir", filepath.Join(dataDir(), "ssh-stats"), "Directory the statistics of each player's public key are kept in. Kept in memory if empty.")
+	flags.StringVar(&output, "output", "256", `Output mode used for the players' terminals. Options are "rgb", "256", and "normal".`)
+	flags.DurationVar(&animation, "animation", 120*time.Millisecond, "Duration of the tile animations after each move. Disabled if 0.")
+	flags.BoolVar(&endless, "endless", false, "Offer to keep playing after reaching the target tile.")
+	flags.DurationVar(&hintTime, "hint-time", 500*time.Millisecond, "Time the solver may spend searching for a hint. Hints are disabled if 0.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := checkNoArgs(flags); err != nil {
+		return err
+	}
+
+	gameOptions, err := gameFlags.options()
+	if err != nil {
+		return err
+	}
+	spawn, err := gameFlags.spawnPolicy()
+	if err != nil {
+		return err
+	}
+	signer, err := sshserver.LoadHostKey(hostKey)
+	if err != nil {
+		return err
+	}
+	uiOptions := []terminalui.Option{
+		parseOutModeOption(output),
+		terminalui.WithAnimationDuration(animation),
+	}
+	if endless {
+		uiOptions = append(uiOptions, terminalui.WithEndless())
+	}
+	if hintTime > 0 {
+		uiOptions = append(uiOptions, terminalui.WithAdvisor(newExpectimaxAdvisor(solver.WithSpawnPolicy(spawn)), hintTime))
+	}
+	srv := sshserver.New(addr, signer,
+		sshserver.WithStatsDir(statsDir),
+		sshserver.WithGameOptions(gameOptions...),
+		sshserver.WithUIOptions(uiOptions...),
+	)
+	return serve(srv, addr)
+}
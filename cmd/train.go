@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/ntuple"
+)
+
+func runTrain(args []string) error {
+	var (
+		games      int
+		weights    string
+		patterns   string
+		rate       float64
+		lambda     float64
+		seed       int64
+		spawn      string
+		progress   int
+		checkpoint int
+	)
+	flags := newFlagSet("train", "")
+	flags.IntVar(&games, "games", 10000, "Number of games to train on.")
+	flags.StringVar(&weights, "weights", defaultWeightsPath(), "File the network is saved to. Training continues from it if it exists.")
+	flags.StringVar(&patterns, "patterns", "default", `Tuples of the network when starting from scratch, "default" or "large". Large networks play stronger but need 256MB of memory.`)
+	flags.Float64Var(&rate, "rate", 0.1, "Learning rate.")
+	flags.Float64Var(&lambda, "lambda", 0, "λ of TD(λ), 0 learns with TD(0).")
+	flags.Int64Var(&seed, "seed", 1, "Seed the training games are derived from, the same seed always learns the same weights.")
+	flags.StringVar(&spawn, "spawn", "classic", "Odds and number of the tiles added after each move. Options are "+quoteAll(game.SpawnPresets())+".")
+	flags.IntVar(&progress, "progress", 1000, "Number of games summarised in each progress line.")
+	flags.IntVar(&checkpoint, "checkpoint", 1000, "Number of games between saves of the network, it is also saved when training stops.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := checkNoArgs(flags); err != nil {
+		return err
+	}
+
+	if games < 1 || progress < 1 || checkpoint < 0 {
+		return errors.New("-games and -progress must be at least 1, and -checkpoint must not be negative")
+	}
+	if rate <= 0 || rate > 1 || lambda < 0 || lambda > 1 {
+		return errors.New("-rate must be greater than 0 and at most 1, and -lambda must be between 0 and 1")
+	}
+	policy, ok := game.SpawnPreset(spawn)
+	if !ok {
+		return fmt.Errorf("unknown spawn policy %q, options are %s", spawn, quoteAll(game.SpawnPresets()))
+	}
+	network, err := ntuple.Load(weights)
+	switch {
+	case err == nil:
+		fmt.Printf("continuing from %d games in %s\n", network.Games(), weights)
+	case errors.Is(err, fs.ErrNotExist):
+		if network, err = newNetwork(patterns); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	trainer := ntuple.NewTrainer(network,
+		ntuple.WithLearningRate(rate),
+		ntuple.WithLambda(lambda),
+		ntuple.WithSeed(seed),
+		ntuple.WithSpawnPolicy(policy),
+		ntuple.WithProgress(progress, printProgress),
+		ntuple.WithCheckpoint(weights, checkpoint),
+	)
+	err = trainer.Train(ctx, games)
+	if errors.Is(err, context.Canceled) {
+		fmt.Printf("stopped after %d games, saved to %s\n", network.Games(), weights)
+		return nil
+	}
+	return err
+}
+
+func newNetwork(patterns string) (*ntuple.Network, error) {
+	switch patterns {
+	case "default":
+		return ntuple.NewNetwork(ntuple.DefaultPatterns()...)
+	case "large":
+		return ntuple.NewNetwork(ntuple.LargePatterns()...)
+	default:
+		return nil, fmt.Errorf("unknown patterns %q, expected \"default\" or \"large\"", patterns)
+	}
+}
+
+func printProgress(p ntuple.Progress) {
+	fmt.Printf("games %-8d avg score %-8.0f best %-8d moves %-6.0f 2048 rate %5.1f%%  highest %-6d %.0f games/s\n",
+		p.Games, p.AverageScore, p.BestScore, p.AverageMoves, 100*p.WinRate, p.HighestTile, p.GamesPerSecond)
+}
+
+// defaultWeightsPath is where train saves the network and the ntuple player loads it from
+func defaultWeightsPath() string {
+	return filepath.Join(dataDir(), "ntuple.weights")
+}
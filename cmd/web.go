@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/brandenc40/2048/web"
+)
+
+func runWeb(args []string) error {
+	var addr string
+	flags := newFlagSet("web", "")
+	gameFlags := addGameFlags(flags)
+	flags.StringVar(&addr, "addr", ":8080", "Address the web server listens on.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := checkNoArgs(flags); err != nil {
+		return err
+	}
+
+	gameOptions, err := gameFlags.options()
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           web.New(web.WithGameOptions(gameOptions...)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return serve(srv, addr)
+}
@@ -0,0 +1,42 @@
+package game
+
+import "fmt"
+
+// Adversary chooses the tiles added to the board in place of random spawns, see WithAdversary
+type Adversary interface {
+	// Place returns the tile to add to the cells, which have at least one empty cell. The tile must be placed
+	// in an empty cell and have a value the spawn policy can spawn.
+	Place(cells Cells, spawn SpawnPolicy) Tile
+}
+
+// WithAdversary lets the adversary choose the cell and value of every tile added to the board, including
+// those on a new board. The spawn policy still decides how many tiles are added and the values the adversary
+// may choose from. Random values are no longer drawn for new tiles, so the same seed does not play out the
+// same game without the adversary. The adversary is not part of save data and must be given again when a
+// saved game is loaded.
+func WithAdversary(adversary Adversary) Option {
+	return adversaryOption{adversary: adversary}
+}
+
+type adversaryOption struct {
+	adversary Adversary
+}
+
+func (o adversaryOption) apply(b *board) {
+	if o.adversary == nil {
+		panic("WithAdversary: adversary must not be nil")
+	}
+	b.adversary = o.adversary
+}
+
+// placeAdversary asks the adversary for the next tile on the cells, panicking if the tile breaks the rules
+func (b *board) placeAdversary(cells Cells) Tile {
+	tile := b.adversary.Place(cells.Clone(), b.spawn.clone())
+	if tile.Row < 0 || tile.Row >= b.rows || tile.Col < 0 || tile.Col >= b.cols || cells[tile.Row][tile.Col] != _emptyCell {
+		panic(fmt.Sprintf("Adversary: cannot place a tile at row %d column %d", tile.Row, tile.Col))
+	}
+	if b.spawn.Probability(tile.Value) == 0 {
+		panic(fmt.Sprintf("Adversary: the spawn policy does not spawn %d", tile.Value))
+	}
+	return tile
+}
@@ -0,0 +1,65 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// lastCellAdversary places the policy's largest value in the last empty cell
+type lastCellAdversary struct{}
+
+func (lastCellAdversary) Place(cells Cells, spawn SpawnPolicy) Tile {
+	empty := cells.EmptyPositions()
+	return Tile{Position: empty[len(empty)-1], Value: spawn.MaxValue()}
+}
+
+// fixedAdversary always places the same tile
+type fixedAdversary Tile
+
+func (a fixedAdversary) Place(Cells, SpawnPolicy) Tile { return Tile(a) }
+
+func TestNewController_WithAdversary(t *testing.T) {
+	gc := NewController(WithSize(2, 3), WithAdversary(lastCellAdversary{}))
+	equal(t, Cells{{0, 0, 0}, {0, 4, 4}}, gc.GetCells())
+
+	res := gc.Move(DirectionLeft)
+	equal(t, true, res.Changed)
+	equal(t, []Tile{{Position: Position{Row: 1, Col: 2}, Value: 4}}, res.Spawned)
+	equal(t, Cells{{0, 0, 0}, {8, 0, 4}}, gc.GetCells())
+}
+
+func TestNewController_WithAdversaryBitboard(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	reference := NewController(WithSpawnPolicy(HardSpawns()), WithAdversary(lastCellAdversary{}))
+	bitboard := NewController(WithSpawnPolicy(HardSpawns()), WithAdversary(lastCellAdversary{}), WithBitboard())
+	equal(t, reference.GetCells(), bitboard.GetCells())
+	for !reference.Lost() {
+		direction := directions[rng.Intn(len(directions))]
+		equal(t, reference.Shift(direction), bitboard.Shift(direction))
+		equal(t, reference.GetCells(), bitboard.GetCells())
+		if t.Failed() {
+			t.Fatalf("engines diverged after %d moves", reference.GetMoveCount())
+		}
+	}
+}
+
+func TestWithAdversary_invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		adversary Adversary
+		expected  string
+	}{
+		{"nil", nil, "WithAdversary: adversary must not be nil"},
+		{"outside the board", fixedAdversary{Position: Position{Row: 4, Col: 0}, Value: 2}, "Adversary: cannot place a tile at row 4 column 0"},
+		{"occupied cell", fixedAdversary{Position: Position{Row: 0, Col: 0}, Value: 2}, "Adversary: cannot place a tile at row 0 column 0"},
+		{"value not spawned", fixedAdversary{Position: Position{Row: 0, Col: 0}, Value: 8}, "Adversary: the spawn policy does not spawn 8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				equal(t, tt.expected, recover())
+			}()
+			NewController(WithAdversary(tt.adversary))
+		})
+	}
+}
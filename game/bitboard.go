@@ -0,0 +1,375 @@
+package game
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Bitboard is a 4x4 board packed into a uint64. Each cell is stored as a 4-bit exponent, 0 for an empty
+// cell and n for a cell with the value 2^n, so tiles range from 2 to 32768. The cell at row r and column c
+// is held in bits 16r+4c to 16r+4c+3. Shifts use precomputed row and column lookup tables, which makes a
+// Bitboard much cheaper to search than Cells. Cells of 32768 can no longer merge.
+type Bitboard uint64
+
+const (
+	_bitboardSize = 4
+	_colMask      = 0x000F000F000F000F
+	_maxExponent  = 15
+)
+
+// BitboardFromCells packs a 4x4 board into a Bitboard. False is returned if the board is not 4x4 or holds
+// a value that is not a power of two up to 32768.
+func BitboardFromCells(cells Cells) (Bitboard, bool) {
+	if cells.Rows() != _bitboardSize || cells.Cols() != _bitboardSize {
+		return 0, false
+	}
+	var b Bitboard
+	for rowIdx, row := range cells {
+		for colIdx, value := range row {
+			if value == _emptyCell {
+				continue
+			}
+			if value == 1 || value > _maxBitboardCell || !isPowerOfTwo(value) {
+				return 0, false
+			}
+			b = b.withExponent(rowIdx*_bitboardSize+colIdx, uint8(bits.TrailingZeros32(value)))
+		}
+	}
+	return b, true
+}
+
+// Cells unpacks the Bitboard into a 4x4 board
+func (b Bitboard) Cells() Cells {
+	cells := newCells(_bitboardSize, _bitboardSize)
+	for i := 0; i < _bitboardSize*_bitboardSize; i++ {
+		if exp := b.exponent(i); exp != 0 {
+			cells[i/_bitboardSize][i%_bitboardSize] = 1 << exp
+		}
+	}
+	return cells
+}
+
+// Value returns the value of the cell at the given row and column, 0 if the cell is empty
+func (b Bitboard) Value(row, col int) uint32 {
+	if exp := b.exponent(row*_bitboardSize + col); exp != 0 {
+		return 1 << exp
+	}
+	return _emptyCell
+}
+
+// Exponent returns the exponent of the cell at the given row and column, 0 if the cell is empty
+func (b Bitboard) Exponent(row, col int) uint8 {
+	return b.exponent(row*_bitboardSize + col)
+}
+
+// WithExponent returns a copy of the Bitboard with the cell at the given row and column set to 2^exp, or
+// emptied when exp is 0. Exponents above 15 panic.
+func (b Bitboard) WithExponent(row, col int, exp uint8) Bitboard {
+	if exp > _maxExponent {
+		panic("Bitboard.WithExponent: exponent must be at most 15")
+	}
+	return b.withExponent(row*_bitboardSize+col, exp)
+}
+
+// Shift returns the Bitboard shifted in the Direction provided, along with the points earned by merging.
+// As with Cells.Shift no random cell is added. The board is unchanged when the returned Bitboard is equal
+// to b.
+func (b Bitboard) Shift(direction Direction) (shifted Bitboard, score uint32) {
+	shifted, score, _ = b.slide(direction)
+	return shifted, score
+}
+
+// EmptyCount returns the number of empty cells
+func (b Bitboard) EmptyCount() int {
+	// fold each nibble into its lowest bit, which is set when the nibble is non-zero
+	x := uint64(b)
+	x |= x >> 2
+	x |= x >> 1
+	return _bitboardSize*_bitboardSize - bits.OnesCount64(x&0x1111111111111111)
+}
+
+// MaxValue returns the largest cell value on the board
+func (b Bitboard) MaxValue() uint32 {
+	var max uint8
+	for i := 0; i < _bitboardSize*_bitboardSize; i++ {
+		if exp := b.exponent(i); exp > max {
+			max = exp
+		}
+	}
+	if max == 0 {
+		return _emptyCell
+	}
+	return 1 << max
+}
+
+// CanMove returns true if a shift in any direction would change the board
+func (b Bitboard) CanMove() bool {
+	if b.EmptyCount() > 0 {
+		return true
+	}
+	for _, direction := range [...]Direction{DirectionLeft, DirectionUp} {
+		if shifted, _ := b.Shift(direction); shifted != b {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// internal methods
+//
+
+func (b Bitboard) exponent(idx int) uint8 {
+	return uint8(b>>(4*uint(idx))) & 0xF
+}
+
+func (b Bitboard) withExponent(idx int, exp uint8) Bitboard {
+	shift := 4 * uint(idx)
+	return b&^(0xF<<shift) | Bitboard(exp)<<shift
+}
+
+func (b Bitboard) row(row int) uint16 {
+	return uint16(b >> (16 * uint(row)))
+}
+
+// col packs a column into the same layout as a row, with the top cell in the lowest nibble
+func (b Bitboard) col(col int) uint16 {
+	x := uint64(b>>(4*uint(col))) & _colMask
+	return uint16(x | x>>12 | x>>24 | x>>36)
+}
+
+// slide returns the shifted board, the points earned and a mask with bit n set for each 2^n tile that was
+// created by a merge
+func (b Bitboard) slide(direction Direction) (shifted Bitboard, score uint32, merged uint16) {
+	switch direction {
+	case DirectionLeft:
+		for r := 0; r < _bitboardSize; r++ {
+			row := b.row(r)
+			shifted |= Bitboard(leftTable.rows[row]) << (16 * uint(r))
+			score += leftTable.score[row]
+			merged |= leftTable.merged[row]
+		}
+	case DirectionRight:
+		for r := 0; r < _bitboardSize; r++ {
+			row := b.row(r)
+			shifted |= Bitboard(rightTable.rows[row]) << (16 * uint(r))
+			score += rightTable.score[row]
+			merged |= rightTable.merged[row]
+		}
+	case DirectionUp:
+		for c := 0; c < _bitboardSize; c++ {
+			col := b.col(c)
+			shifted |= Bitboard(leftTable.cols[col]) << (4 * uint(c))
+			score += leftTable.score[col]
+			merged |= leftTable.merged[col]
+		}
+	case DirectionDown:
+		for c := 0; c < _bitboardSize; c++ {
+			col := b.col(c)
+			shifted |= Bitboard(rightTable.cols[col]) << (4 * uint(c))
+			score += rightTable.score[col]
+			merged |= rightTable.merged[col]
+		}
+	default:
+		shifted = b
+	}
+	return
+}
+
+// lineTable holds the result of sliding every possible packed line of four exponents toward one end
+type lineTable struct {
+	rows   [1 << 16]uint16 // shifted line in row layout
+	cols   [1 << 16]uint64 // shifted line unpacked into column layout
+	score  [1 << 16]uint32 // points earned by merging
+	merged [1 << 16]uint16 // bit n is set for each 2^n tile created by a merge
+}
+
+// leftTable slides lines toward the lowest nibble (left or up), rightTable toward the highest (right or down)
+var leftTable, rightTable = newLineTables()
+
+func newLineTables() (left, right *lineTable) {
+	left, right = new(lineTable), new(lineTable)
+	for line := 0; line < 1<<16; line++ {
+		shifted, score, merged := slideLine(uint16(line))
+		left.rows[line] = shifted
+		left.cols[line] = unpackCol(shifted)
+		left.score[line] = score
+		left.merged[line] = merged
+
+		// sliding right is sliding the reversed line left
+		reversed := reverseLine(uint16(line))
+		shifted, score, merged = slideLine(reversed)
+		right.rows[line] = reverseLine(shifted)
+		right.cols[line] = unpackCol(reverseLine(shifted))
+		right.score[line] = score
+		right.merged[line] = merged
+	}
+	return
+}
+
+// slideLine slides a packed line of four exponents toward the lowest nibble, merging as board.shiftLeft does
+func slideLine(line uint16) (shifted uint16, score uint32, merged uint16) {
+	var (
+		out            [_bitboardSize]uint16
+		n              int
+		lastCellMerged bool
+	)
+	for i := 0; i < _bitboardSize; i++ {
+		exp := line >> (4 * uint(i)) & 0xF
+		if exp == 0 {
+			continue
+		}
+		if n > 0 && out[n-1] == exp && exp != _maxExponent && !lastCellMerged {
+			out[n-1]++
+			score += 1 << out[n-1]
+			merged |= 1 << out[n-1]
+			lastCellMerged = true
+		} else {
+			out[n] = exp
+			n++
+			lastCellMerged = false
+		}
+	}
+	for i, exp := range out {
+		shifted |= exp << (4 * uint(i))
+	}
+	return
+}
+
+func reverseLine(line uint16) uint16 {
+	return line>>12 | line>>4&0x00F0 | line<<4&0x0F00 | line<<12
+}
+
+// unpackCol spreads a packed line into column layout, the nibble at index i moving to row i of column 0
+func unpackCol(line uint16) uint64 {
+	x := uint64(line)
+	return (x | x<<12 | x<<24 | x<<36) & _colMask
+}
+
+// bitboardController is a Controller backed by a Bitboard. It shares the bookkeeping of the embedded board,
+// keeping board.cells in step with the Bitboard only around the slower operations: Move, Undo, Redo, Reset
+// and save and load. Shift runs on the Bitboard alone and spawns tiles with the same random draws as board,
+// so both engines play identical games from the same seed.
+type bitboardController struct {
+	board
+	state Bitboard
+}
+
+var _ Controller = (*bitboardController)(nil)
+
+func (c *bitboardController) Shift(direction Direction) bool { return c.shift(direction) }
+func (c *bitboardController) Lost() bool                     { return !c.state.CanMove() }
+func (c *bitboardController) GetCells() Cells                { return c.state.Cells() }
+
+func (c *bitboardController) Move(direction Direction) ShiftResult {
+	c.cells = c.state.Cells()
+	defer c.loadCells()
+	return c.board.Move(direction)
+}
+
+func (c *bitboardController) Reset() {
+	c.board.Reset()
+	c.loadCells()
+}
+
+func (c *bitboardController) Undo() bool {
+	c.cells = c.state.Cells()
+	defer c.loadCells()
+	return c.board.Undo()
+}
+
+func (c *bitboardController) Redo() bool {
+	c.cells = c.state.Cells()
+	defer c.loadCells()
+	return c.board.Redo()
+}
+
+func (c *bitboardController) MarshalBinary() ([]byte, error) {
+	c.cells = c.state.Cells()
+	return c.board.MarshalBinary()
+}
+
+func (c *bitboardController) UnmarshalBinary(data []byte) error {
+	save, err := decodeSave(data)
+	if err != nil {
+		return err
+	}
+	if save.Rows != _bitboardSize || save.Cols != _bitboardSize {
+		return fmt.Errorf("%w: the bitboard engine only supports 4x4 boards, got %dx%d",
+			ErrIncompatibleSave, save.Rows, save.Cols)
+	}
+	if save.Spawn.MaxValue() > _maxBitboardCell {
+		return fmt.Errorf("%w: the bitboard engine only supports spawn values up to %d",
+			ErrIncompatibleSave, _maxBitboardCell)
+	}
+	for _, s := range append([]saveSnapshot{save.State}, append(save.History.Undo, save.History.Redo...)...) {
+		if s.Cells.MaxValue() > _maxBitboardCell {
+			return fmt.Errorf("%w: the bitboard engine only supports cells up to %d",
+				ErrIncompatibleSave, _maxBitboardCell)
+		}
+	}
+	c.load(save)
+	c.loadCells()
+	return nil
+}
+
+func newBitboardController(b board) *bitboardController {
+	if b.rows != _bitboardSize || b.cols != _bitboardSize {
+		panic("WithBitboard: the bitboard engine only supports 4x4 boards")
+	}
+	if b.spawn.MaxValue() > _maxBitboardCell {
+		panic("WithBitboard: the bitboard engine only supports spawn values up to 32768")
+	}
+	c := &bitboardController{board: b}
+	c.loadCells()
+	return c
+}
+
+// loadCells packs board.cells into the Bitboard
+func (c *bitboardController) loadCells() {
+	c.state, _ = BitboardFromCells(c.cells)
+}
+
+// shift mirrors board.shift on the Bitboard
+func (c *bitboardController) shift(direction Direction) bool {
+	var before snapshot
+	if c.history.depth > 0 {
+		c.cells = c.state.Cells()
+		before = c.snapshot()
+	}
+	shifted, score, merged := c.state.slide(direction)
+	if shifted == c.state {
+		return false
+	}
+	c.state = shifted
+	c.score += uint64(score)
+	if c.winTarget <= _maxBitboardCell && merged&uint16(c.winTarget) != 0 {
+		c.won = true
+	}
+	c.moves++
+	c.history.record(before)
+	for n := 0; n < c.spawn.PerMove && c.state.EmptyCount() > 0; n++ {
+		c.fillRandom()
+	}
+	return true
+}
+
+// fillRandom mirrors board.fillRandom for a single tile, picking the empty cell in row then column order
+func (c *bitboardController) fillRandom() {
+	if c.adversary != nil {
+		tile := c.placeAdversary(c.state.Cells())
+		c.state = c.state.withExponent(tile.Row*_bitboardSize+tile.Col, uint8(bits.TrailingZeros32(tile.Value)))
+		return
+	}
+	nth := c.rng.Intn(c.state.EmptyCount())
+	for i := 0; ; i++ {
+		if c.state.exponent(i) != 0 {
+			continue
+		}
+		if nth == 0 {
+			c.state = c.state.withExponent(i, uint8(bits.TrailingZeros32(c.randomStartCell())))
+			return
+		}
+		nth--
+	}
+}
@@ -0,0 +1,157 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+var directions = [...]Direction{DirectionLeft, DirectionUp, DirectionRight, DirectionDown}
+
+func TestBitboard_cells(t *testing.T) {
+	cells := Cells{
+		{2, 0, 0, 32768},
+		{0, 4, 0, 0},
+		{0, 0, 8, 0},
+		{1024, 0, 0, 16},
+	}
+	b, ok := BitboardFromCells(cells)
+	equal(t, true, ok)
+	equal(t, cells, b.Cells())
+	equal(t, uint32(32768), b.Value(0, 3))
+	equal(t, uint8(10), b.Exponent(3, 0))
+	equal(t, 10, b.EmptyCount())
+	equal(t, uint32(32768), b.MaxValue())
+	equal(t, uint32(64), b.WithExponent(1, 1, 6).Value(1, 1))
+
+	_, ok = BitboardFromCells(Cells{{65536, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}})
+	equal(t, false, ok)
+	_, ok = BitboardFromCells(Cells{{3, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}})
+	equal(t, false, ok)
+	_, ok = BitboardFromCells(newCells(3, 3))
+	equal(t, false, ok)
+}
+
+func TestBitboard_Shift(t *testing.T) {
+	// every direction on random boards must match the reference shift of Cells
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		cells := newCells(_bitboardSize, _bitboardSize)
+		for rowIdx := range cells {
+			for colIdx := range cells[rowIdx] {
+				if exp := rng.Intn(15); exp > 0 && rng.Intn(4) > 0 {
+					cells[rowIdx][colIdx] = 1 << uint(rng.Intn(exp)+1)
+				}
+			}
+		}
+		b, _ := BitboardFromCells(cells)
+		for _, direction := range directions {
+			expected, expectedScore, changed := cells.Shift(direction)
+			shifted, score := b.Shift(direction)
+			equal(t, expected, shifted.Cells())
+			equal(t, expectedScore, uint64(score))
+			equal(t, changed, shifted != b)
+		}
+	}
+}
+
+func TestBitboard_maxCell(t *testing.T) {
+	b, _ := BitboardFromCells(Cells{
+		{32768, 32768, 16384, 16384},
+		{2, 4, 2, 4},
+		{4, 2, 4, 2},
+		{2, 4, 2, 4},
+	})
+	shifted, score := b.Shift(DirectionLeft)
+	equal(t, []uint32{32768, 32768, 32768, 0}, shifted.Cells()[0])
+	equal(t, uint32(32768), score)
+
+	full, _ := BitboardFromCells(Cells{
+		{32768, 32768, 2, 4},
+		{4, 2, 4, 2},
+		{2, 4, 2, 4},
+		{4, 2, 4, 2},
+	})
+	equal(t, false, full.CanMove())
+	equal(t, true, b.CanMove())
+}
+
+func TestNewController_WithBitboard(t *testing.T) {
+	// both engines must play the same game move for move from the same seed
+	for seed := int64(0); seed < 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		reference := NewController(WithSeed(seed), WithWinTarget(256))
+		bitboard := NewController(WithSeed(seed), WithWinTarget(256), WithBitboard())
+		equal(t, reference.GetCells(), bitboard.GetCells())
+		for !reference.Lost() {
+			direction := directions[rng.Intn(len(directions))]
+			equal(t, reference.Shift(direction), bitboard.Shift(direction))
+			equal(t, reference.GetCells(), bitboard.GetCells())
+			equal(t, reference.GetScore(), bitboard.GetScore())
+			equal(t, reference.GetMoveCount(), bitboard.GetMoveCount())
+			equal(t, reference.Won(), bitboard.Won())
+			equal(t, reference.Lost(), bitboard.Lost())
+			if t.Failed() {
+				t.Fatalf("engines diverged with seed %d after %d moves", seed, reference.GetMoveCount())
+			}
+		}
+		equal(t, true, bitboard.Lost())
+	}
+}
+
+func TestBitboardController_history(t *testing.T) {
+	gc := NewController(WithSeed(3), WithBitboard())
+	start := gc.GetCells()
+	for _, direction := range directions {
+		gc.Shift(direction)
+	}
+	moved := gc.GetCells()
+	res := gc.Move(DirectionLeft)
+	equal(t, res.Changed, gc.GetMoveCount() == 5)
+
+	for gc.Undo() {
+	}
+	equal(t, start, gc.GetCells())
+	equal(t, uint64(0), gc.GetScore())
+	for i := 0; i < 4; i++ {
+		gc.Redo()
+	}
+	equal(t, moved, gc.GetCells())
+
+	data, err := gc.MarshalBinary()
+	equal(t, nil, err)
+	loaded, err := LoadController(data, WithBitboard())
+	equal(t, nil, err)
+	equal(t, moved, loaded.GetCells())
+	equal(t, gc.GetScore(), loaded.GetScore())
+	equal(t, gc.Shift(DirectionUp), loaded.Shift(DirectionUp))
+	equal(t, gc.GetCells(), loaded.GetCells())
+
+	large, _ := NewController(WithSize(5, 5), WithSeed(1)).MarshalBinary()
+	_, err = LoadController(large, WithBitboard())
+	equal(t, true, err != nil)
+}
+
+func BenchmarkBitboard_Shift(b *testing.B) {
+	board, _ := BitboardFromCells(Cells{
+		{2, 2, 8, 0},
+		{4, 2, 8, 0},
+		{8, 0, 8, 2},
+		{4, 2, 8, 0},
+	})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		board.Shift(directions[i%len(directions)])
+	}
+}
+
+func BenchmarkBitboardController_Shift(b *testing.B) {
+	gc := NewController(WithSeed(1), WithHistoryDepth(0), WithBitboard())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !gc.Shift(directions[i%len(directions)]) && gc.Lost() {
+			gc.Reset()
+		}
+	}
+}
@@ -6,19 +6,32 @@ import (
 )
 
 const (
-	_boardSize = 4
-	_emptyCell = 0
-	_wonCell   = 2048
+	_defaultBoardSize = 4
+	_minBoardSize     = 2
+	_emptyCell        = 0
+	_defaultWinTarget = 2048
+	// _maxCell is the largest value a cell can hold, cells with this value can no longer merge
+	_maxCell = 1 << 31
+	// _maxBitboardCell is the largest value a cell can hold on the bitboard engine
+	_maxBitboardCell = 1 << 15
 )
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
 type board struct {
-	cells Cells
-	score uint32
-	won   bool
+	cells     Cells
+	rows      int
+	cols      int
+	score     uint64
+	won       bool
+	winTarget uint32
+	maxCell   uint32
+	spawn     SpawnPolicy
+	adversary Adversary
+	moves     int
+	seed      int64
+	src       *countingSource
+	rng       *rand.Rand
+	history   history
+	bitboard  bool
 }
 
 //
@@ -27,67 +40,132 @@ type board struct {
 
 var _ Controller = (*board)(nil)
 
-func (b *board) Shift(direction Direction) bool { return b.shift(direction) }
+func (b *board) Shift(direction Direction) bool { return b.shift(direction, nil) }
 func (b *board) Won() bool                      { return b.won }
+func (b *board) WinTarget() uint32              { return b.winTarget }
 func (b *board) Lost() bool                     { return b.noMovesRemaining() }
-func (b *board) GetScore() uint32               { return b.score }
-func (b *board) GetCells() Cells                { return b.cells }
-func (b *board) Reset()                         { *b = initNewBoard() }
+func (b *board) GetScore() uint64               { return b.score }
+func (b *board) GetMoveCount() int              { return b.moves }
+func (b *board) GetCells() Cells                { return b.cells.Clone() }
+func (b *board) Reset()                         { b.reseed(b.rng.Int63()); b.reset() }
+func (b *board) Seed() int64                    { return b.seed }
+func (b *board) Undo() bool                     { return b.undo() }
+func (b *board) Redo() bool                     { return b.redo() }
+func (b *board) CanUndo() bool                  { return b.history.canUndo() }
+func (b *board) CanRedo() bool                  { return b.history.canRedo() }
+func (b *board) UndosRemaining() int            { return b.history.undosRemaining() }
+func (b *board) SpawnPolicy() SpawnPolicy       { return b.spawn.clone() }
+func (b *board) MaxCell() uint32                { return b.maxCell }
+
+func (b *board) Move(direction Direction) ShiftResult {
+	res := ShiftResult{Direction: direction}
+	b.shift(direction, &res)
+	return res
+}
 
 //
 // internal methods
 //
 
-func initNewBoard() board {
-	b := board{}
-	// add two random cells
-	b.fillRandom()
-	b.fillRandom()
+func initNewBoard(options ...Option) board {
+	b := board{
+		rows:      _defaultBoardSize,
+		cols:      _defaultBoardSize,
+		seed:      time.Now().UnixNano(),
+		src:       &countingSource{src: rand.NewSource(0)},
+		history:   newHistory(),
+		winTarget: _defaultWinTarget,
+		maxCell:   _maxCell,
+		spawn:     ClassicSpawns(),
+	}
+	for _, option := range options {
+		option.apply(&b)
+	}
+	b.rng = rand.New(b.src)
+	b.reseed(b.seed)
+	b.reset()
 	return b
 }
 
-// shift cells in the given direction and fill a random cell if the board has changed
-func (b *board) shift(direction Direction) (hasChanged bool) {
+// reseed restarts the random number generator from the given seed
+func (b *board) reseed(seed int64) {
+	b.seed = seed
+	b.src.Seed(seed)
+}
+
+// reset clears the board and its history and adds the starting random cells
+func (b *board) reset() {
+	b.cells = newCells(b.rows, b.cols)
+	b.score = 0
+	b.won = false
+	b.moves = 0
+	b.history.clear()
+	b.fillRandom(b.spawn.Start)
+}
+
+// shift cells in the given direction and fill random cells if the board has changed. The details of the
+// shift are recorded in res when it is not nil.
+func (b *board) shift(direction Direction, res *ShiftResult) (hasChanged bool) {
+	var before snapshot
+	if b.history.depth > 0 {
+		before = b.snapshot()
+	}
+	scoreBefore := b.score
+	hasChanged = b.slide(direction, res)
+	if hasChanged {
+		b.moves++
+		b.history.record(before)
+		spawned := b.fillRandom(b.spawn.PerMove)
+		if res != nil {
+			res.Changed = true
+			res.ScoreDelta = b.score - scoreBefore
+			res.Spawned = append(res.Spawned, spawned...)
+		}
+	}
+	return
+}
+
+// slide and merge cells in the given direction without adding a random cell
+func (b *board) slide(direction Direction, res *ShiftResult) (hasChanged bool) {
 	switch direction {
 	case DirectionLeft:
-		hasChanged = b.shiftLeft()
+		hasChanged = b.shiftLeft(res)
 	case DirectionRight:
-		hasChanged = b.shiftRight()
+		hasChanged = b.shiftRight(res)
 	case DirectionUp:
-		hasChanged = b.shiftUp()
+		hasChanged = b.shiftUp(res)
 	case DirectionDown:
-		hasChanged = b.shiftDown()
-	}
-	if hasChanged {
-		b.fillRandom()
+		hasChanged = b.shiftDown(res)
 	}
 	return
 }
 
 // for each row, merge each column left
-func (b *board) shiftLeft() (hasChanged bool) {
-	for rowIdx := 0; rowIdx < _boardSize; rowIdx++ {
+func (b *board) shiftLeft(res *ShiftResult) (hasChanged bool) {
+	for rowIdx := 0; rowIdx < b.rows; rowIdx++ {
 		var (
 			newColIdx      int
 			lastCellMerged bool
 		)
-		for colIdx := 0; colIdx < _boardSize; colIdx++ {
+		for colIdx := 0; colIdx < b.cols; colIdx++ {
 			curCell := b.getCell(rowIdx, colIdx)
 			if curCell != _emptyCell {
-				if newColIdx > 0 && b.getCell(rowIdx, newColIdx-1) == curCell && !lastCellMerged {
+				if newColIdx > 0 && b.getCell(rowIdx, newColIdx-1) == curCell && curCell != b.maxCell && !lastCellMerged {
 					b.doubleCell(rowIdx, newColIdx-1)
+					res.merge(Position{rowIdx, colIdx}, Position{rowIdx, newColIdx - 1}, curCell)
 					lastCellMerged = true
 					hasChanged = true
 				} else {
 					if b.setCell(rowIdx, newColIdx, curCell) {
 						hasChanged = true
 					}
+					res.move(Position{rowIdx, colIdx}, Position{rowIdx, newColIdx}, curCell)
 					newColIdx++
 					lastCellMerged = false
 				}
 			}
 		}
-		for ; newColIdx < _boardSize; newColIdx++ {
+		for ; newColIdx < b.cols; newColIdx++ {
 			b.setCell(rowIdx, newColIdx, _emptyCell)
 		}
 	}
@@ -95,23 +173,25 @@ func (b *board) shiftLeft() (hasChanged bool) {
 }
 
 // for each row, merge each column right
-func (b *board) shiftRight() (hasChanged bool) {
-	for rowIdx := _boardSize - 1; rowIdx >= 0; rowIdx-- {
+func (b *board) shiftRight(res *ShiftResult) (hasChanged bool) {
+	for rowIdx := b.rows - 1; rowIdx >= 0; rowIdx-- {
 		var (
-			newColIdx      = _boardSize - 1
+			newColIdx      = b.cols - 1
 			lastCellMerged bool
 		)
-		for colIdx := _boardSize - 1; colIdx >= 0; colIdx-- {
+		for colIdx := b.cols - 1; colIdx >= 0; colIdx-- {
 			curCell := b.getCell(rowIdx, colIdx)
 			if curCell != _emptyCell {
-				if newColIdx < _boardSize-1 && b.getCell(rowIdx, newColIdx+1) == curCell && !lastCellMerged {
+				if newColIdx < b.cols-1 && b.getCell(rowIdx, newColIdx+1) == curCell && curCell != b.maxCell && !lastCellMerged {
 					b.doubleCell(rowIdx, newColIdx+1)
+					res.merge(Position{rowIdx, colIdx}, Position{rowIdx, newColIdx + 1}, curCell)
 					lastCellMerged = true
 					hasChanged = true
 				} else {
 					if b.setCell(rowIdx, newColIdx, curCell) {
 						hasChanged = true
 					}
+					res.move(Position{rowIdx, colIdx}, Position{rowIdx, newColIdx}, curCell)
 					newColIdx--
 					lastCellMerged = false
 				}
@@ -125,29 +205,31 @@ func (b *board) shiftRight() (hasChanged bool) {
 }
 
 // for each column, merge each row up
-func (b *board) shiftUp() (hasChanged bool) {
-	for colIdx := 0; colIdx < _boardSize; colIdx++ {
+func (b *board) shiftUp(res *ShiftResult) (hasChanged bool) {
+	for colIdx := 0; colIdx < b.cols; colIdx++ {
 		var (
 			newRowIdx      int
 			lastCellMerged bool
 		)
-		for rowIdx := 0; rowIdx < _boardSize; rowIdx++ {
+		for rowIdx := 0; rowIdx < b.rows; rowIdx++ {
 			curCell := b.getCell(rowIdx, colIdx)
 			if curCell != _emptyCell {
-				if newRowIdx > 0 && b.getCell(newRowIdx-1, colIdx) == curCell && !lastCellMerged {
+				if newRowIdx > 0 && b.getCell(newRowIdx-1, colIdx) == curCell && curCell != b.maxCell && !lastCellMerged {
 					b.doubleCell(newRowIdx-1, colIdx)
+					res.merge(Position{rowIdx, colIdx}, Position{newRowIdx - 1, colIdx}, curCell)
 					lastCellMerged = true
 					hasChanged = true
 				} else {
 					if b.setCell(newRowIdx, colIdx, curCell) {
 						hasChanged = true
 					}
+					res.move(Position{rowIdx, colIdx}, Position{newRowIdx, colIdx}, curCell)
 					newRowIdx++
 					lastCellMerged = false
 				}
 			}
 		}
-		for ; newRowIdx < _boardSize; newRowIdx++ {
+		for ; newRowIdx < b.rows; newRowIdx++ {
 			b.cells[newRowIdx][colIdx] = _emptyCell
 		}
 	}
@@ -155,23 +237,25 @@ func (b *board) shiftUp() (hasChanged bool) {
 }
 
 // for each column, merge each row down
-func (b *board) shiftDown() (hasChanged bool) {
-	for colIdx := _boardSize - 1; colIdx >= 0; colIdx-- {
+func (b *board) shiftDown(res *ShiftResult) (hasChanged bool) {
+	for colIdx := b.cols - 1; colIdx >= 0; colIdx-- {
 		var (
-			newRowIdx      = _boardSize - 1
+			newRowIdx      = b.rows - 1
 			lastCellMerged bool
 		)
-		for rowIdx := _boardSize - 1; rowIdx >= 0; rowIdx-- {
+		for rowIdx := b.rows - 1; rowIdx >= 0; rowIdx-- {
 			curCell := b.getCell(rowIdx, colIdx)
 			if curCell != _emptyCell {
-				if newRowIdx < _boardSize-1 && b.getCell(newRowIdx+1, colIdx) == curCell && !lastCellMerged {
+				if newRowIdx < b.rows-1 && b.getCell(newRowIdx+1, colIdx) == curCell && curCell != b.maxCell && !lastCellMerged {
 					b.doubleCell(newRowIdx+1, colIdx)
+					res.merge(Position{rowIdx, colIdx}, Position{newRowIdx + 1, colIdx}, curCell)
 					lastCellMerged = true
 					hasChanged = true
 				} else {
 					if b.setCell(newRowIdx, colIdx, curCell) {
 						hasChanged = true
 					}
+					res.move(Position{rowIdx, colIdx}, Position{newRowIdx, colIdx}, curCell)
 					lastCellMerged = false
 					newRowIdx--
 				}
@@ -184,21 +268,22 @@ func (b *board) shiftDown() (hasChanged bool) {
 	return
 }
 
-func (b *board) getCell(row, col int) uint16 {
+func (b *board) getCell(row, col int) uint32 {
 	return b.cells[row][col]
 }
 
-// doubleCell double the cell value, mark if it's a winning cell, and update the score
+// doubleCell double the cell value, mark if it's a winning cell, and update the score. Play continues after
+// the game is won so the board can be taken beyond the win target.
 func (b *board) doubleCell(row, col int) {
-	b.cells[row][col] <<= 1 // double the uint16 using bitshift left
-	if b.cells[row][col] == _wonCell {
+	b.cells[row][col] <<= 1 // double the uint32 using bitshift left
+	if b.cells[row][col] == b.winTarget {
 		b.won = true
 	}
-	b.score += (uint32)(b.cells[row][col])
+	b.score += (uint64)(b.cells[row][col])
 }
 
 // setCell sets a cell value, if the cell is already set to the given value, the boolean returned will be false
-func (b *board) setCell(row, col int, val uint16) bool {
+func (b *board) setCell(row, col int, val uint32) bool {
 	if b.cells[row][col] != val {
 		b.cells[row][col] = val
 		return true
@@ -207,18 +292,21 @@ func (b *board) setCell(row, col int, val uint16) bool {
 }
 
 func (b *board) noMovesRemaining() bool {
-	for row := 0; row < _boardSize; row++ {
-		for col := 0; col < _boardSize; col++ {
+	for row := 0; row < b.rows; row++ {
+		for col := 0; col < b.cols; col++ {
 			curCell := b.getCell(row, col)
 			if curCell == _emptyCell {
 				return false
 			}
+			if curCell == b.maxCell {
+				continue
+			}
 			// up
 			if row > 0 && b.getCell(row-1, col) == curCell {
 				return false
 			}
 			// down
-			if row < _boardSize-1 && b.getCell(row+1, col) == curCell {
+			if row < b.rows-1 && b.getCell(row+1, col) == curCell {
 				return false
 			}
 			// left
@@ -226,7 +314,7 @@ func (b *board) noMovesRemaining() bool {
 				return false
 			}
 			// right
-			if col < _boardSize-1 && b.getCell(row, col+1) == curCell {
+			if col < b.cols-1 && b.getCell(row, col+1) == curCell {
 				return false
 			}
 		}
@@ -234,28 +322,70 @@ func (b *board) noMovesRemaining() bool {
 	return true
 }
 
-func (b *board) fillRandom() {
-	emptyCells := b.getEmptyCells()
-	randomEmpty := emptyCells[rand.Intn(len(emptyCells))]
-	b.cells[randomEmpty[0]][randomEmpty[1]] = randomStartCell()
+// fillRandom places a random start value in up to n random empty cells, returning the new tiles. The
+// adversary chooses the tiles instead when there is one.
+func (b *board) fillRandom(n int) []Tile {
+	tiles := make([]Tile, 0, n)
+	for ; n > 0; n-- {
+		emptyCells := b.getEmptyCells()
+		if len(emptyCells) == 0 {
+			break
+		}
+		var tile Tile
+		if b.adversary != nil {
+			tile = b.placeAdversary(b.cells)
+		} else {
+			randomEmpty := emptyCells[b.rng.Intn(len(emptyCells))]
+			tile = Tile{Position: Position{randomEmpty[0], randomEmpty[1]}, Value: b.randomStartCell()}
+		}
+		b.cells[tile.Row][tile.Col] = tile.Value
+		tiles = append(tiles, tile)
+	}
+	return tiles
 }
 
-func (b *board) getEmptyCells() [][2]uint8 {
-	emptyCells := make([][2]uint8, 0, _boardSize*_boardSize)
-	for rowIdx := uint8(0); rowIdx < _boardSize; rowIdx++ {
-		for colIdx := uint8(0); colIdx < _boardSize; colIdx++ {
+func (b *board) getEmptyCells() [][2]int {
+	emptyCells := make([][2]int, 0, b.rows*b.cols)
+	for rowIdx := 0; rowIdx < b.rows; rowIdx++ {
+		for colIdx := 0; colIdx < b.cols; colIdx++ {
 			if b.cellIsEmpty(rowIdx, colIdx) {
-				emptyCells = append(emptyCells, [2]uint8{rowIdx, colIdx})
+				emptyCells = append(emptyCells, [2]int{rowIdx, colIdx})
 			}
 		}
 	}
 	return emptyCells
 }
 
-func (b *board) cellIsEmpty(row, col uint8) bool {
+func (b *board) cellIsEmpty(row, col int) bool {
 	return b.cells[row][col] == _emptyCell
 }
 
-func randomStartCell() uint16 {
-	return [2]uint16{2, 4}[rand.Intn(2)]
+func (b *board) randomStartCell() uint32 {
+	return b.spawn.draw(b.rng)
+}
+
+// countingSource wraps a rand.Source and counts the number of values drawn since it was last seeded
+type countingSource struct {
+	src   rand.Source
+	draws uint64
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.draws = 0
+}
+
+// rewind moves the source to the position reached after the given number of draws from the seed
+func (s *countingSource) rewind(seed int64, draws uint64) {
+	if draws < s.draws {
+		s.Seed(seed)
+	}
+	for s.draws < draws {
+		s.Int63()
+	}
 }
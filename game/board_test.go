@@ -1,6 +1,7 @@
 package game
 
 import (
+	"math/rand"
 	"reflect"
 	"testing"
 )
@@ -9,61 +10,106 @@ const a = 35 << 1
 
 func TestBoard_shift(t *testing.T) {
 	b := initNewBoard()
-	b.cells = [4][4]uint16{
+	b.cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
 		{4, 2, 8, 0},
 	}
-	hasChanged := b.shiftDown()
-	equal(t, [4]uint16{2, 0, 0, 0}, b.cells[0])
-	equal(t, [4]uint16{4, 0, 0, 0}, b.cells[1])
-	equal(t, [4]uint16{8, 2, 16, 0}, b.cells[2])
-	equal(t, [4]uint16{4, 4, 16, 2}, b.cells[3])
+	hasChanged := b.shiftDown(nil)
+	equal(t, []uint32{2, 0, 0, 0}, b.cells[0])
+	equal(t, []uint32{4, 0, 0, 0}, b.cells[1])
+	equal(t, []uint32{8, 2, 16, 0}, b.cells[2])
+	equal(t, []uint32{4, 4, 16, 2}, b.cells[3])
 	equal(t, true, hasChanged)
 
-	hasChanged = b.shiftRight()
-	equal(t, [4]uint16{0, 0, 0, 2}, b.cells[0])
-	equal(t, [4]uint16{0, 0, 0, 4}, b.cells[1])
-	equal(t, [4]uint16{0, 8, 2, 16}, b.cells[2])
-	equal(t, [4]uint16{0, 8, 16, 2}, b.cells[3])
+	hasChanged = b.shiftRight(nil)
+	equal(t, []uint32{0, 0, 0, 2}, b.cells[0])
+	equal(t, []uint32{0, 0, 0, 4}, b.cells[1])
+	equal(t, []uint32{0, 8, 2, 16}, b.cells[2])
+	equal(t, []uint32{0, 8, 16, 2}, b.cells[3])
 	equal(t, true, hasChanged)
 
-	hasChanged = b.shiftUp()
-	equal(t, [4]uint16{0, 16, 2, 2}, b.cells[0])
-	equal(t, [4]uint16{0, 0, 16, 4}, b.cells[1])
-	equal(t, [4]uint16{0, 0, 0, 16}, b.cells[2])
-	equal(t, [4]uint16{0, 0, 0, 2}, b.cells[3])
+	hasChanged = b.shiftUp(nil)
+	equal(t, []uint32{0, 16, 2, 2}, b.cells[0])
+	equal(t, []uint32{0, 0, 16, 4}, b.cells[1])
+	equal(t, []uint32{0, 0, 0, 16}, b.cells[2])
+	equal(t, []uint32{0, 0, 0, 2}, b.cells[3])
 	equal(t, true, hasChanged)
 
-	hasChanged = b.shiftLeft()
-	equal(t, [4]uint16{16, 4, 0, 0}, b.cells[0])
-	equal(t, [4]uint16{16, 4, 0, 0}, b.cells[1])
-	equal(t, [4]uint16{16, 0, 0, 0}, b.cells[2])
-	equal(t, [4]uint16{2, 0, 0, 0}, b.cells[3])
+	hasChanged = b.shiftLeft(nil)
+	equal(t, []uint32{16, 4, 0, 0}, b.cells[0])
+	equal(t, []uint32{16, 4, 0, 0}, b.cells[1])
+	equal(t, []uint32{16, 0, 0, 0}, b.cells[2])
+	equal(t, []uint32{2, 0, 0, 0}, b.cells[3])
 	equal(t, true, hasChanged)
 }
 
 func TestBoard_won(t *testing.T) {
 	b := initNewBoard()
-	b.cells = [4][4]uint16{
+	b.cells = Cells{
 		{0, 0, 0, 0},
 		{0, 0, 0, 0},
 		{1024, 0, 0, 0},
 		{1024, 0, 0, 0},
 	}
-	equal(t, true, b.shiftDown())
-	equal(t, [4]uint16{0, 0, 0, 0}, b.cells[0])
-	equal(t, [4]uint16{0, 0, 0, 0}, b.cells[1])
-	equal(t, [4]uint16{0, 0, 0, 0}, b.cells[2])
-	equal(t, [4]uint16{2048, 0, 0, 0}, b.cells[3])
+	equal(t, true, b.shiftDown(nil))
+	equal(t, []uint32{0, 0, 0, 0}, b.cells[0])
+	equal(t, []uint32{0, 0, 0, 0}, b.cells[1])
+	equal(t, []uint32{0, 0, 0, 0}, b.cells[2])
+	equal(t, []uint32{2048, 0, 0, 0}, b.cells[3])
 	equal(t, true, b.won)
-	equal(t, uint32(2048), b.score)
+	equal(t, uint64(2048), b.score)
+}
+
+func TestBoard_winTarget(t *testing.T) {
+	b := initNewBoard(WithWinTarget(64))
+	b.cells = Cells{
+		{32, 32, 0, 0},
+		{64, 64, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	}
+	equal(t, uint32(64), b.WinTarget())
+	equal(t, true, b.shiftLeft(nil))
+	equal(t, true, b.won)
+
+	// play continues past the win target
+	equal(t, []uint32{64, 0, 0, 0}, b.cells[0])
+	equal(t, []uint32{128, 0, 0, 0}, b.cells[1])
+	equal(t, true, b.shiftRight(nil))
+	equal(t, []uint32{0, 0, 0, 64}, b.cells[0])
+	equal(t, true, b.won)
+}
+
+func TestBoard_largeCells(t *testing.T) {
+	b := initNewBoard()
+	b.cells = Cells{
+		{32768, 32768, 65536, 0},
+		{0, 0, 0, 0},
+		{_maxCell, _maxCell, 0, 0},
+		{0, 0, 0, 0},
+	}
+	equal(t, true, b.shiftLeft(nil))
+	equal(t, []uint32{65536, 65536, 0, 0}, b.cells[0])
+	equal(t, []uint32{_maxCell, _maxCell, 0, 0}, b.cells[2])
+	equal(t, true, b.shiftLeft(nil))
+	equal(t, []uint32{131072, 0, 0, 0}, b.cells[0])
+	equal(t, []uint32{_maxCell, _maxCell, 0, 0}, b.cells[2])
+	equal(t, uint64(65536+131072), b.score)
+
+	b.cells = Cells{
+		{_maxCell, _maxCell, 2, 4},
+		{4, 2, 4, 2},
+		{2, 4, 2, 4},
+		{4, 2, 4, 2},
+	}
+	equal(t, true, b.noMovesRemaining())
 }
 
 func TestBoard_noMovesRemaining(t *testing.T) {
 	b := initNewBoard()
-	b.cells = [4][4]uint16{
+	b.cells = Cells{
 		{2, 4, 2, 4},
 		{4, 2, 4, 2},
 		{2, 4, 2, 4},
@@ -71,7 +117,7 @@ func TestBoard_noMovesRemaining(t *testing.T) {
 	}
 	equal(t, true, b.noMovesRemaining())
 
-	b.cells = [4][4]uint16{
+	b.cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
@@ -82,84 +128,201 @@ func TestBoard_noMovesRemaining(t *testing.T) {
 
 func TestBoard_shiftUp(t *testing.T) {
 	b := initNewBoard()
-	b.cells = [4][4]uint16{
+	b.cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
 		{4, 2, 8, 0},
 	}
-	equal(t, true, b.shiftUp())
-	equal(t, [4]uint16{2, 4, 16, 2}, b.cells[0])
-	equal(t, [4]uint16{4, 2, 16, 0}, b.cells[1])
-	equal(t, [4]uint16{8, 0, 0, 0}, b.cells[2])
-	equal(t, [4]uint16{4, 0, 0, 0}, b.cells[3])
-	equal(t, true, b.shiftUp())
-	equal(t, [4]uint16{2, 4, 32, 2}, b.cells[0])
-	equal(t, [4]uint16{4, 2, 0, 0}, b.cells[1])
-	equal(t, [4]uint16{8, 0, 0, 0}, b.cells[2])
-	equal(t, [4]uint16{4, 0, 0, 0}, b.cells[3])
-	equal(t, false, b.shiftUp())
+	equal(t, true, b.shiftUp(nil))
+	equal(t, []uint32{2, 4, 16, 2}, b.cells[0])
+	equal(t, []uint32{4, 2, 16, 0}, b.cells[1])
+	equal(t, []uint32{8, 0, 0, 0}, b.cells[2])
+	equal(t, []uint32{4, 0, 0, 0}, b.cells[3])
+	equal(t, true, b.shiftUp(nil))
+	equal(t, []uint32{2, 4, 32, 2}, b.cells[0])
+	equal(t, []uint32{4, 2, 0, 0}, b.cells[1])
+	equal(t, []uint32{8, 0, 0, 0}, b.cells[2])
+	equal(t, []uint32{4, 0, 0, 0}, b.cells[3])
+	equal(t, false, b.shiftUp(nil))
 }
 
 func TestBoard_shiftDown(t *testing.T) {
 	b := initNewBoard()
-	b.cells = [4][4]uint16{
+	b.cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
 		{4, 2, 8, 0},
 	}
-	equal(t, true, b.shiftDown())
-	equal(t, [4]uint16{2, 0, 0, 0}, b.cells[0])
-	equal(t, [4]uint16{4, 0, 0, 0}, b.cells[1])
-	equal(t, [4]uint16{8, 2, 16, 0}, b.cells[2])
-	equal(t, [4]uint16{4, 4, 16, 2}, b.cells[3])
-	equal(t, true, b.shiftDown())
-	equal(t, [4]uint16{2, 0, 0, 0}, b.cells[0])
-	equal(t, [4]uint16{4, 0, 0, 0}, b.cells[1])
-	equal(t, [4]uint16{8, 2, 0, 0}, b.cells[2])
-	equal(t, [4]uint16{4, 4, 32, 2}, b.cells[3])
-	equal(t, false, b.shiftDown())
+	equal(t, true, b.shiftDown(nil))
+	equal(t, []uint32{2, 0, 0, 0}, b.cells[0])
+	equal(t, []uint32{4, 0, 0, 0}, b.cells[1])
+	equal(t, []uint32{8, 2, 16, 0}, b.cells[2])
+	equal(t, []uint32{4, 4, 16, 2}, b.cells[3])
+	equal(t, true, b.shiftDown(nil))
+	equal(t, []uint32{2, 0, 0, 0}, b.cells[0])
+	equal(t, []uint32{4, 0, 0, 0}, b.cells[1])
+	equal(t, []uint32{8, 2, 0, 0}, b.cells[2])
+	equal(t, []uint32{4, 4, 32, 2}, b.cells[3])
+	equal(t, false, b.shiftDown(nil))
 }
 
 func TestBoard_shiftLeft(t *testing.T) {
 	b := initNewBoard()
-	b.cells = [4][4]uint16{
+	b.cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
 		{4, 2, 8, 0},
 	}
-	equal(t, true, b.shiftLeft())
-	equal(t, [4]uint16{4, 8, 0, 0}, b.cells[0])
-	equal(t, [4]uint16{4, 2, 8, 0}, b.cells[1])
-	equal(t, [4]uint16{16, 2, 0, 0}, b.cells[2])
-	equal(t, [4]uint16{4, 2, 8, 0}, b.cells[3])
-	equal(t, false, b.shiftLeft())
-	equal(t, [4]uint16{4, 8, 0, 0}, b.cells[0])
-	equal(t, [4]uint16{4, 2, 8, 0}, b.cells[1])
-	equal(t, [4]uint16{16, 2, 0, 0}, b.cells[2])
-	equal(t, [4]uint16{4, 2, 8, 0}, b.cells[3])
+	equal(t, true, b.shiftLeft(nil))
+	equal(t, []uint32{4, 8, 0, 0}, b.cells[0])
+	equal(t, []uint32{4, 2, 8, 0}, b.cells[1])
+	equal(t, []uint32{16, 2, 0, 0}, b.cells[2])
+	equal(t, []uint32{4, 2, 8, 0}, b.cells[3])
+	equal(t, false, b.shiftLeft(nil))
+	equal(t, []uint32{4, 8, 0, 0}, b.cells[0])
+	equal(t, []uint32{4, 2, 8, 0}, b.cells[1])
+	equal(t, []uint32{16, 2, 0, 0}, b.cells[2])
+	equal(t, []uint32{4, 2, 8, 0}, b.cells[3])
 }
 
 func TestBoard_shiftRight(t *testing.T) {
 	b := initNewBoard()
-	b.cells = [4][4]uint16{
+	b.cells = Cells{
+		{2, 2, 8, 0},
+		{4, 2, 8, 0},
+		{8, 0, 8, 2},
+		{4, 2, 8, 0},
+	}
+	equal(t, true, b.shiftRight(nil))
+	equal(t, []uint32{0, 0, 4, 8}, b.cells[0])
+	equal(t, []uint32{0, 4, 2, 8}, b.cells[1])
+	equal(t, []uint32{0, 0, 16, 2}, b.cells[2])
+	equal(t, []uint32{0, 4, 2, 8}, b.cells[3])
+	equal(t, false, b.shiftRight(nil))
+	equal(t, []uint32{0, 0, 4, 8}, b.cells[0])
+	equal(t, []uint32{0, 4, 2, 8}, b.cells[1])
+	equal(t, []uint32{0, 0, 16, 2}, b.cells[2])
+	equal(t, []uint32{0, 4, 2, 8}, b.cells[3])
+}
+
+func TestBoard_rectangular(t *testing.T) {
+	b := initNewBoard(WithSize(3, 5))
+	equal(t, 3, b.cells.Rows())
+	equal(t, 5, b.cells.Cols())
+	equal(t, 13, len(b.getEmptyCells()))
+
+	b.cells = Cells{
+		{2, 2, 4, 0, 4},
+		{0, 8, 0, 8, 2},
+		{2, 0, 2, 0, 2},
+	}
+	equal(t, true, b.shiftRight(nil))
+	equal(t, []uint32{0, 0, 0, 4, 8}, b.cells[0])
+	equal(t, []uint32{0, 0, 0, 16, 2}, b.cells[1])
+	equal(t, []uint32{0, 0, 0, 2, 4}, b.cells[2])
+	equal(t, uint64(32), b.score)
+	equal(t, false, b.shiftUp(nil))
+	equal(t, true, b.shiftLeft(nil))
+	equal(t, []uint32{4, 8, 0, 0, 0}, b.cells[0])
+	equal(t, []uint32{16, 2, 0, 0, 0}, b.cells[1])
+	equal(t, []uint32{2, 4, 0, 0, 0}, b.cells[2])
+
+	b.cells = Cells{
+		{2, 4, 2, 4, 2},
+		{4, 2, 4, 2, 4},
+		{2, 4, 2, 4, 2},
+	}
+	equal(t, true, b.noMovesRemaining())
+	b.cells[2][4] = 4
+	equal(t, false, b.noMovesRemaining())
+}
+
+func TestCells_Shift(t *testing.T) {
+	cells := Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
 		{4, 2, 8, 0},
 	}
-	equal(t, true, b.shiftRight())
-	equal(t, [4]uint16{0, 0, 4, 8}, b.cells[0])
-	equal(t, [4]uint16{0, 4, 2, 8}, b.cells[1])
-	equal(t, [4]uint16{0, 0, 16, 2}, b.cells[2])
-	equal(t, [4]uint16{0, 4, 2, 8}, b.cells[3])
-	equal(t, false, b.shiftRight())
-	equal(t, [4]uint16{0, 0, 4, 8}, b.cells[0])
-	equal(t, [4]uint16{0, 4, 2, 8}, b.cells[1])
-	equal(t, [4]uint16{0, 0, 16, 2}, b.cells[2])
-	equal(t, [4]uint16{0, 4, 2, 8}, b.cells[3])
+	shifted, score, changed := cells.Shift(DirectionDown)
+	equal(t, true, changed)
+	equal(t, uint64(36), score)
+	equal(t, Cells{
+		{2, 0, 0, 0},
+		{4, 0, 0, 0},
+		{8, 2, 16, 0},
+		{4, 4, 16, 2},
+	}, shifted)
+	// the original cells are left untouched
+	equal(t, []uint32{2, 2, 8, 0}, cells[0])
+
+	_, score, changed = shifted.Shift(DirectionRight)
+	equal(t, true, changed)
+	equal(t, uint64(8), score)
+	_, score, changed = Cells{{2, 4}, {4, 2}}.Shift(DirectionLeft)
+	equal(t, false, changed)
+	equal(t, uint64(0), score)
+
+	equal(t, []Position{{0, 1}, {0, 2}, {0, 3}, {1, 1}, {1, 2}, {1, 3}, {2, 3}}, shifted.EmptyPositions())
+}
+
+func TestCells_ShiftCapped(t *testing.T) {
+	cells := Cells{{1 << 15, 1 << 15}, {2, 2}}
+	shifted, score, changed := cells.Shift(DirectionLeft)
+	equal(t, true, changed)
+	equal(t, uint64(1<<16+4), score)
+	equal(t, Cells{{1 << 16, 0}, {4, 0}}, shifted)
+
+	// the bitboard engine caps cells at 32768, which no longer merge
+	gc := NewController(WithBitboard())
+	equal(t, uint32(1<<15), gc.MaxCell())
+	equal(t, uint32(1<<31), NewController().MaxCell())
+	shifted, score, changed = cells.ShiftCapped(DirectionLeft, gc.MaxCell())
+	equal(t, true, changed)
+	equal(t, uint64(4), score)
+	equal(t, Cells{{1 << 15, 1 << 15}, {4, 0}}, shifted)
+	_, _, changed = Cells{{1 << 15, 1 << 15}, {2, 4}}.ShiftCapped(DirectionLeft, gc.MaxCell())
+	equal(t, false, changed)
+}
+
+func TestNewController_WithSize(t *testing.T) {
+	gc := NewController(WithSize(6, 3))
+	cells := gc.GetCells()
+	equal(t, 6, cells.Rows())
+	equal(t, 3, cells.Cols())
+
+	// the returned cells must be a copy of the board
+	cells[0][0], cells[5][2] = 1, 1
+	equal(t, false, reflect.DeepEqual(cells, gc.GetCells()))
+
+	gc.Reset()
+	equal(t, 6, gc.GetCells().Rows())
+}
+
+func TestNewController_WithSeed(t *testing.T) {
+	moves := []Direction{DirectionLeft, DirectionUp, DirectionRight, DirectionDown, DirectionLeft, DirectionDown}
+
+	gc1 := NewController(WithSeed(42))
+	gc2 := NewController(WithSeed(42), WithRandSource(rand.NewSource(7)))
+	equal(t, int64(42), gc1.Seed())
+	equal(t, gc1.GetCells(), gc2.GetCells())
+	for _, direction := range moves {
+		equal(t, gc1.Shift(direction), gc2.Shift(direction))
+		equal(t, gc1.GetCells(), gc2.GetCells())
+	}
+	equal(t, gc1.GetScore(), gc2.GetScore())
+
+	// resetting derives the next game from the previous one
+	gc1.Reset()
+	gc2.Reset()
+	equal(t, gc1.Seed(), gc2.Seed())
+	equal(t, gc1.GetCells(), gc2.GetCells())
+
+	gc3 := NewController(WithSeed(gc1.Seed()))
+	equal(t, gc1.GetCells(), gc3.GetCells())
 }
 
 func equal(t *testing.T, expected, actual interface{}) {
@@ -168,9 +331,15 @@ func equal(t *testing.T, expected, actual interface{}) {
 	}
 }
 
+func copyCells(dst, src Cells) {
+	for rowIdx := range src {
+		copy(dst[rowIdx], src[rowIdx])
+	}
+}
+
 func BenchmarkBoard_Shift(b *testing.B) {
 	board := initNewBoard()
-	var cells = [4][4]uint16{
+	var cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
@@ -179,14 +348,14 @@ func BenchmarkBoard_Shift(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		board.cells = cells
+		copyCells(board.cells, cells)
 		board.Shift(DirectionDown)
 	}
 }
 
 func BenchmarkBoard_noMovesRemaining(b *testing.B) {
 	board := initNewBoard()
-	var cells = [4][4]uint16{
+	var cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
@@ -195,14 +364,14 @@ func BenchmarkBoard_noMovesRemaining(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		board.cells = cells
+		copyCells(board.cells, cells)
 		board.noMovesRemaining()
 	}
 }
 
 func BenchmarkBoard_shiftLeft(b *testing.B) {
 	board := initNewBoard()
-	var cells = [4][4]uint16{
+	var cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
@@ -211,14 +380,14 @@ func BenchmarkBoard_shiftLeft(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		board.cells = cells
-		board.shiftLeft()
+		copyCells(board.cells, cells)
+		board.shiftLeft(nil)
 	}
 }
 
 func BenchmarkBoard_shiftRight(b *testing.B) {
 	board := initNewBoard()
-	var cells = [4][4]uint16{
+	var cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
@@ -227,14 +396,14 @@ func BenchmarkBoard_shiftRight(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		board.cells = cells
-		board.shiftRight()
+		copyCells(board.cells, cells)
+		board.shiftRight(nil)
 	}
 }
 
 func BenchmarkBoard_shiftUp(b *testing.B) {
 	board := initNewBoard()
-	var cells = [4][4]uint16{
+	var cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
@@ -243,14 +412,14 @@ func BenchmarkBoard_shiftUp(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		board.cells = cells
-		board.shiftUp()
+		copyCells(board.cells, cells)
+		board.shiftUp(nil)
 	}
 }
 
 func BenchmarkBoard_shiftDown(b *testing.B) {
 	board := initNewBoard()
-	var cells = [4][4]uint16{
+	var cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
@@ -259,14 +428,14 @@ func BenchmarkBoard_shiftDown(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		board.cells = cells
-		board.shiftDown()
+		copyCells(board.cells, cells)
+		board.shiftDown(nil)
 	}
 }
 
 func BenchmarkBoard_fillRandom(b *testing.B) {
 	board := initNewBoard()
-	var cells = [4][4]uint16{
+	var cells = Cells{
 		{2, 2, 8, 0},
 		{4, 2, 8, 0},
 		{8, 0, 8, 2},
@@ -275,13 +444,14 @@ func BenchmarkBoard_fillRandom(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		board.cells = cells
-		board.fillRandom()
+		copyCells(board.cells, cells)
+		board.fillRandom(1)
 	}
 }
 
 func BenchmarkBoard_randomStartCell(b *testing.B) {
+	board := initNewBoard()
 	for i := 0; i < b.N; i++ {
-		_ = randomStartCell()
+		_ = board.randomStartCell()
 	}
 }
@@ -14,28 +14,155 @@ const (
 	DirectionDown
 )
 
-// Cells that make up the game board
-type Cells [_boardSize][_boardSize]uint16
+// Cells that make up the game board, indexed by row then column
+type Cells [][]uint32
+
+// Rows returns the number of rows on the board
+func (c Cells) Rows() int { return len(c) }
+
+// Cols returns the number of columns on the board
+func (c Cells) Cols() int {
+	if len(c) == 0 {
+		return 0
+	}
+	return len(c[0])
+}
+
+// Clone returns a deep copy of the cells
+func (c Cells) Clone() Cells {
+	clone := newCells(c.Rows(), c.Cols())
+	for rowIdx, row := range c {
+		copy(clone[rowIdx], row)
+	}
+	return clone
+}
+
+// MaxValue returns the largest cell value on the board
+func (c Cells) MaxValue() uint32 {
+	var max uint32
+	for _, row := range c {
+		for _, value := range row {
+			if value > max {
+				max = value
+			}
+		}
+	}
+	return max
+}
+
+// Shift returns a copy of the cells shifted in the Direction provided, along with the points earned by
+// merging and whether any cell changed. Unlike Controller.Shift no random cell is added, which makes it
+// suitable for searching future moves. Cells merge up to the largest value of the default engine, use
+// ShiftCapped to search the moves of a game with a lower Controller.MaxCell.
+func (c Cells) Shift(direction Direction) (shifted Cells, score uint64, changed bool) {
+	return c.ShiftCapped(direction, _maxCell)
+}
+
+// ShiftCapped shifts the cells like Shift, except that cells with the value maxCell can no longer merge
+func (c Cells) ShiftCapped(direction Direction, maxCell uint32) (shifted Cells, score uint64, changed bool) {
+	b := board{cells: c.Clone(), rows: c.Rows(), cols: c.Cols(), maxCell: maxCell}
+	changed = b.slide(direction, nil)
+	return b.cells, b.score, changed
+}
+
+// EmptyPositions returns the positions of every empty cell, ordered by row then column
+func (c Cells) EmptyPositions() []Position {
+	var empty []Position
+	for rowIdx, row := range c {
+		for colIdx, value := range row {
+			if value == _emptyCell {
+				empty = append(empty, Position{Row: rowIdx, Col: colIdx})
+			}
+		}
+	}
+	return empty
+}
+
+// sum returns the total of every cell value
+func (c Cells) sum() uint64 {
+	var total uint64
+	for _, row := range c {
+		for _, value := range row {
+			total += uint64(value)
+		}
+	}
+	return total
+}
+
+// newCells allocates an empty rows x cols board backed by a single slice
+func newCells(rows, cols int) Cells {
+	backing := make([]uint32, rows*cols)
+	cells := make(Cells, rows)
+	for rowIdx := range cells {
+		cells[rowIdx] = backing[rowIdx*cols : (rowIdx+1)*cols : (rowIdx+1)*cols]
+	}
+	return cells
+}
 
 // Controller for controlling and viewing the game board
 type Controller interface {
 	// Shift the board in the Direction provided. True is returned if rows were changed, if no action
 	// was possible then false is returned
 	Shift(direction Direction) (changed bool)
-	// Won returns true if the board has a cell equal to 2048
+	// Move shifts the board in the Direction provided like Shift, returning a ShiftResult that describes
+	// where each tile moved, which tiles merged, the points earned and the tiles spawned
+	Move(direction Direction) ShiftResult
+	// Won returns true once the board has reached a cell equal to the win target. Shifting is still
+	// possible after the game is won
 	Won() bool
+	// WinTarget returns the cell value needed to win the game, 2048 unless configured otherwise
+	WinTarget() uint32
 	// Lost returns true if there are no more possible moves to be made
 	Lost() bool
 	// GetScore returns the current score of the game
-	GetScore() uint32
-	// GetCells returns the game board cell values
+	GetScore() uint64
+	// GetMoveCount returns the number of shifts that changed the board in the current game
+	GetMoveCount() int
+	// GetCells returns a copy of the game board cell values
 	GetCells() Cells
-	// Reset the game board back to initial state with new random values
+	// Reset the game board back to initial state with new random values. The new game is seeded from
+	// the random values of the previous game, so a sequence of games remains reproducible
 	Reset()
+	// Seed returns the seed used to generate the random values of the current game
+	Seed() int64
+	// Undo reverts the last Shift, including the random cell it added. False is returned if there is
+	// nothing to undo or the undo limit has been reached
+	Undo() bool
+	// Redo reapplies the last undone Shift. False is returned if there is nothing to redo
+	Redo() bool
+	// CanUndo returns true if a call to Undo would succeed
+	CanUndo() bool
+	// CanRedo returns true if a call to Redo would succeed
+	CanRedo() bool
+	// UndosRemaining returns the number of undos left in the current game, or -1 if undos are unlimited
+	UndosRemaining() int
+	// SpawnPolicy returns the policy deciding which random tiles are added to the board
+	SpawnPolicy() SpawnPolicy
+	// MaxCell returns the largest value a cell can hold, cells with this value can no longer merge
+	MaxCell() uint32
+	// MarshalBinary encodes the full game state, including the undo history and random number generator
+	// position, into a versioned save format
+	MarshalBinary() ([]byte, error)
+	// UnmarshalBinary restores a game state encoded by MarshalBinary. An error wrapping ErrCorruptSave or
+	// ErrIncompatibleSave is returned if the data cannot be restored, leaving the game unchanged
+	UnmarshalBinary(data []byte) error
 }
 
-// NewController builds a new 2048 game board manager
-func NewController() Controller {
-	b := initNewBoard()
+// NewController builds a new 2048 game board manager. By default the board is 4x4.
+func NewController(options ...Option) Controller {
+	b := initNewBoard(options...)
+	if b.bitboard {
+		return newBitboardController(b)
+	}
 	return &b
 }
+
+// LoadController builds a game controller from save data encoded by Controller.MarshalBinary. The board
+// size, win target and undo settings are taken from the save data rather than the options.
+func LoadController(data []byte, options ...Option) (Controller, error) {
+	gc := NewController(options...)
+	if err := gc.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return gc, nil
+}
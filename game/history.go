@@ -0,0 +1,107 @@
+package game
+
+const _defaultHistoryDepth = 100
+
+// snapshot of the board state taken before a shift, used to undo and redo moves
+type snapshot struct {
+	cells Cells
+	score uint64
+	won   bool
+	moves int
+	draws uint64
+}
+
+// history of board snapshots for undoing and redoing shifts
+type history struct {
+	depth     int
+	undoLimit int // negative when undos are unlimited
+	undosUsed int
+	undo      []snapshot
+	redo      []snapshot
+}
+
+func newHistory() history {
+	return history{
+		depth:     _defaultHistoryDepth,
+		undoLimit: -1,
+	}
+}
+
+// clear drops all snapshots and restores the undo budget
+func (h *history) clear() {
+	h.undo = h.undo[:0]
+	h.redo = h.redo[:0]
+	h.undosUsed = 0
+}
+
+// record a snapshot taken before a successful shift, dropping the oldest snapshot once the depth is reached
+func (h *history) record(s snapshot) {
+	if h.depth == 0 {
+		return
+	}
+	if len(h.undo) == h.depth {
+		copy(h.undo, h.undo[1:])
+		h.undo = h.undo[:h.depth-1]
+	}
+	h.undo = append(h.undo, s)
+	h.redo = h.redo[:0]
+}
+
+func (h *history) canUndo() bool {
+	return len(h.undo) > 0 && (h.undoLimit < 0 || h.undosRemaining() > 0)
+}
+
+func (h *history) canRedo() bool {
+	return len(h.redo) > 0
+}
+
+// undosRemaining returns the number of undos left in the budget, or -1 if undos are unlimited
+func (h *history) undosRemaining() int {
+	if h.undoLimit < 0 {
+		return -1
+	}
+	return h.undoLimit - h.undosUsed
+}
+
+func (b *board) snapshot() snapshot {
+	return snapshot{
+		cells: b.cells.Clone(),
+		score: b.score,
+		won:   b.won,
+		moves: b.moves,
+		draws: b.src.draws,
+	}
+}
+
+func (b *board) restore(s snapshot) {
+	b.cells = s.cells
+	b.score = s.score
+	b.won = s.won
+	b.moves = s.moves
+	b.src.rewind(b.seed, s.draws)
+}
+
+// undo the last shift, returning false if there is nothing to undo or the undo budget is spent
+func (b *board) undo() bool {
+	if !b.history.canUndo() {
+		return false
+	}
+	last := len(b.history.undo) - 1
+	b.history.redo = append(b.history.redo, b.snapshot())
+	b.restore(b.history.undo[last])
+	b.history.undo = b.history.undo[:last]
+	b.history.undosUsed++
+	return true
+}
+
+// redo the last undone shift, returning false if there is nothing to redo
+func (b *board) redo() bool {
+	if !b.history.canRedo() {
+		return false
+	}
+	last := len(b.history.redo) - 1
+	b.history.undo = append(b.history.undo, b.snapshot())
+	b.restore(b.history.redo[last])
+	b.history.redo = b.history.redo[:last]
+	return true
+}
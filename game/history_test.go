@@ -0,0 +1,79 @@
+package game
+
+import "testing"
+
+func TestBoard_undoRedo(t *testing.T) {
+	b := initNewBoard(WithSeed(1))
+	b.cells = Cells{
+		{2, 2, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 4},
+	}
+	equal(t, false, b.CanUndo())
+	equal(t, false, b.Undo())
+
+	start := b.snapshot()
+	equal(t, true, b.Shift(DirectionLeft))
+	afterLeft := b.snapshot()
+	equal(t, true, b.Shift(DirectionDown))
+	afterDown := b.snapshot()
+
+	equal(t, true, b.Undo())
+	equal(t, afterLeft, b.snapshot())
+	equal(t, true, b.Undo())
+	equal(t, start, b.snapshot())
+	equal(t, false, b.CanUndo())
+
+	equal(t, true, b.Redo())
+	equal(t, afterLeft, b.snapshot())
+
+	// the random source is rewound, so replaying the move spawns the same cell
+	equal(t, true, b.Shift(DirectionDown))
+	equal(t, afterDown, b.snapshot())
+	equal(t, false, b.CanRedo())
+	equal(t, -1, b.UndosRemaining())
+}
+
+func TestBoard_undoLimit(t *testing.T) {
+	b := initNewBoard(WithSeed(1), WithUndoLimit(1))
+	b.cells = Cells{
+		{2, 2, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 4},
+	}
+	equal(t, 1, b.UndosRemaining())
+	b.Shift(DirectionLeft)
+	b.Shift(DirectionRight)
+	equal(t, true, b.Undo())
+	equal(t, 0, b.UndosRemaining())
+	equal(t, false, b.CanUndo())
+	equal(t, false, b.Undo())
+	equal(t, true, b.Redo())
+
+	b.Reset()
+	equal(t, 1, b.UndosRemaining())
+	equal(t, false, b.CanUndo())
+}
+
+func TestBoard_historyDepth(t *testing.T) {
+	b := initNewBoard(WithSeed(1), WithHistoryDepth(2))
+	for _, direction := range []Direction{DirectionLeft, DirectionRight, DirectionLeft, DirectionRight} {
+		b.cells = Cells{
+			{2, 0, 0, 4},
+			{0, 0, 0, 0},
+			{0, 0, 0, 0},
+			{0, 0, 0, 0},
+		}
+		b.Shift(direction)
+	}
+	equal(t, true, b.Undo())
+	equal(t, true, b.Undo())
+	equal(t, false, b.Undo())
+
+	b = initNewBoard(WithHistoryDepth(0))
+	b.Shift(DirectionLeft)
+	b.Shift(DirectionRight)
+	equal(t, false, b.CanUndo())
+}
@@ -0,0 +1,136 @@
+package game
+
+import "math/rand"
+
+// Option configures the game board built by NewController
+type Option interface {
+	apply(b *board)
+}
+
+// WithSize sets the number of rows and columns on the game board. Both dimensions must be at least 2.
+func WithSize(rows, cols int) Option {
+	return sizeOption{rows: rows, cols: cols}
+}
+
+type sizeOption struct {
+	rows, cols int
+}
+
+func (o sizeOption) apply(b *board) {
+	if o.rows < _minBoardSize || o.cols < _minBoardSize {
+		panic("WithSize: board dimensions must be at least 2x2")
+	}
+	b.rows = o.rows
+	b.cols = o.cols
+}
+
+// WithSeed sets the seed used for random cell placement. Two controllers built with the same seed
+// produce identical boards for the same sequence of moves. By default a time based seed is used.
+func WithSeed(seed int64) Option {
+	return seedOption{seed: seed}
+}
+
+type seedOption struct {
+	seed int64
+}
+
+func (o seedOption) apply(b *board) {
+	b.seed = o.seed
+}
+
+// WithRandSource sets the source of random values used for random cell placement. The controller
+// seeds the source with the seed set by WithSeed, or a time based seed if none is given.
+func WithRandSource(src rand.Source) Option {
+	return randSourceOption{src: src}
+}
+
+type randSourceOption struct {
+	src rand.Source
+}
+
+func (o randSourceOption) apply(b *board) {
+	if o.src == nil {
+		panic("WithRandSource: source must not be nil")
+	}
+	b.src = &countingSource{src: o.src}
+}
+
+// WithHistoryDepth sets the number of moves that can be undone. A depth of 0 disables undo, the default is 100.
+func WithHistoryDepth(depth int) Option {
+	return historyDepthOption{depth: depth}
+}
+
+type historyDepthOption struct {
+	depth int
+}
+
+func (o historyDepthOption) apply(b *board) {
+	if o.depth < 0 {
+		panic("WithHistoryDepth: depth must not be negative")
+	}
+	b.history.depth = o.depth
+}
+
+// WithUndoLimit limits the number of undos available in each game. By default undos are unlimited.
+func WithUndoLimit(limit int) Option {
+	return undoLimitOption{limit: limit}
+}
+
+type undoLimitOption struct {
+	limit int
+}
+
+func (o undoLimitOption) apply(b *board) {
+	if o.limit < 0 {
+		panic("WithUndoLimit: limit must not be negative")
+	}
+	b.history.undoLimit = o.limit
+}
+
+// WithWinTarget sets the cell value that wins the game. The target must be a power of two of at least 4,
+// the default is 2048.
+func WithWinTarget(target uint32) Option {
+	return winTargetOption{target: target}
+}
+
+type winTargetOption struct {
+	target uint32
+}
+
+func (o winTargetOption) apply(b *board) {
+	if o.target < 4 || !isPowerOfTwo(o.target) {
+		panic("WithWinTarget: target must be a power of two of at least 4")
+	}
+	b.winTarget = o.target
+}
+
+// WithSpawnPolicy sets which tiles are added at the start of each game and after every move. By default
+// ClassicSpawns is used. The policy must pass SpawnPolicy.Validate.
+func WithSpawnPolicy(policy SpawnPolicy) Option {
+	return spawnPolicyOption{policy: policy}
+}
+
+type spawnPolicyOption struct {
+	policy SpawnPolicy
+}
+
+func (o spawnPolicyOption) apply(b *board) {
+	if err := o.policy.Validate(); err != nil {
+		panic("WithSpawnPolicy: " + err.Error())
+	}
+	b.spawn = o.policy.clone()
+}
+
+// WithBitboard plays the game on the bitboard engine, which packs the board into a single uint64 and shifts
+// it with precomputed lookup tables. The engine only supports 4x4 boards and cells up to 32768, which can no
+// longer merge, and panics if combined with any other size.
+func WithBitboard() Option {
+	return bitboardOption{}
+}
+
+type bitboardOption struct{}
+
+func (o bitboardOption) apply(b *board) {
+	b.bitboard = true
+	b.maxCell = _maxBitboardCell
+}
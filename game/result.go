@@ -0,0 +1,55 @@
+package game
+
+// Position of a cell on the game board
+type Position struct {
+	Row int
+	Col int
+}
+
+// Tile is a cell value at a position on the game board
+type Tile struct {
+	Position
+	Value uint32
+}
+
+// TileMove describes where a tile went during a shift. Tiles that did not move have equal From and To positions
+type TileMove struct {
+	From  Position
+	To    Position
+	Value uint32
+	// Merged is true if the tile merged with another tile at the To position
+	Merged bool
+}
+
+// ShiftResult describes everything that happened during a shift
+type ShiftResult struct {
+	// Direction the board was shifted in
+	Direction Direction
+	// Changed is true if any tile moved or merged
+	Changed bool
+	// Moves lists every tile that was on the board before the shift in the order they were processed
+	Moves []TileMove
+	// Merges lists the tiles created by merging along with their new value
+	Merges []Tile
+	// ScoreDelta is the number of points earned by the shift
+	ScoreDelta uint64
+	// Spawned lists the random tiles added after the shift
+	Spawned []Tile
+}
+
+// move records a tile moving from one position to another. Safe to call on a nil result.
+func (r *ShiftResult) move(from, to Position, value uint32) {
+	if r == nil {
+		return
+	}
+	r.Moves = append(r.Moves, TileMove{From: from, To: to, Value: value})
+}
+
+// merge records a tile merging into the tile at the given position. Safe to call on a nil result.
+func (r *ShiftResult) merge(from, to Position, value uint32) {
+	if r == nil {
+		return
+	}
+	r.Moves = append(r.Moves, TileMove{From: from, To: to, Value: value, Merged: true})
+	r.Merges = append(r.Merges, Tile{Position: to, Value: value << 1})
+}
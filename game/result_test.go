@@ -0,0 +1,50 @@
+package game
+
+import "testing"
+
+func TestBoard_Move(t *testing.T) {
+	b := initNewBoard(WithSeed(1))
+	b.cells = Cells{
+		{2, 2, 8, 0},
+		{0, 0, 0, 0},
+		{0, 4, 0, 4},
+		{0, 0, 0, 0},
+	}
+	res := b.Move(DirectionLeft)
+	equal(t, DirectionLeft, res.Direction)
+	equal(t, true, res.Changed)
+	equal(t, uint64(12), res.ScoreDelta)
+	equal(t, []TileMove{
+		{From: Position{0, 0}, To: Position{0, 0}, Value: 2},
+		{From: Position{0, 1}, To: Position{0, 0}, Value: 2, Merged: true},
+		{From: Position{0, 2}, To: Position{0, 1}, Value: 8},
+		{From: Position{2, 1}, To: Position{2, 0}, Value: 4},
+		{From: Position{2, 3}, To: Position{2, 0}, Value: 4, Merged: true},
+	}, res.Moves)
+	equal(t, []Tile{
+		{Position: Position{0, 0}, Value: 4},
+		{Position: Position{2, 0}, Value: 8},
+	}, res.Merges)
+
+	equal(t, 1, len(res.Spawned))
+	spawned := res.Spawned[0]
+	equal(t, spawned.Value, b.cells[spawned.Row][spawned.Col])
+}
+
+func TestBoard_MoveUnchanged(t *testing.T) {
+	b := initNewBoard()
+	b.cells = Cells{
+		{2, 0, 0, 0},
+		{4, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	}
+	res := b.Move(DirectionLeft)
+	equal(t, false, res.Changed)
+	equal(t, []TileMove{
+		{From: Position{0, 0}, To: Position{0, 0}, Value: 2},
+		{From: Position{1, 0}, To: Position{1, 0}, Value: 4},
+	}, res.Moves)
+	equal(t, 0, len(res.Merges))
+	equal(t, 0, len(res.Spawned))
+}
@@ -0,0 +1,219 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SaveVersion is the version of the save format written by Controller.MarshalBinary. Version 1 saves, which
+// do not record a spawn policy, are still loaded and continue with EvenSpawns.
+const SaveVersion = 2
+
+var (
+	// ErrCorruptSave is returned when save data cannot be decoded or describes an invalid game
+	ErrCorruptSave = errors.New("corrupt save data")
+	// ErrIncompatibleSave is returned when save data was written with an unsupported save format version
+	ErrIncompatibleSave = errors.New("incompatible save data")
+)
+
+type saveFile struct {
+	Version   int          `json:"version"`
+	Rows      int          `json:"rows"`
+	Cols      int          `json:"cols"`
+	WinTarget uint32       `json:"win_target"`
+	Seed      int64        `json:"seed"`
+	Spawn     SpawnPolicy  `json:"spawn"`
+	State     saveSnapshot `json:"state"`
+	History   saveHistory  `json:"history"`
+}
+
+type saveSnapshot struct {
+	Cells Cells  `json:"cells"`
+	Score uint64 `json:"score"`
+	Won   bool   `json:"won"`
+	Moves int    `json:"moves"`
+	Draws uint64 `json:"draws"`
+}
+
+type saveHistory struct {
+	Depth     int            `json:"depth"`
+	UndoLimit int            `json:"undo_limit"`
+	UndosUsed int            `json:"undos_used"`
+	Undo      []saveSnapshot `json:"undo"`
+	Redo      []saveSnapshot `json:"redo"`
+}
+
+//
+// Controller interface implementation
+//
+
+func (b *board) MarshalBinary() ([]byte, error) {
+	save := saveFile{
+		Version:   SaveVersion,
+		Rows:      b.rows,
+		Cols:      b.cols,
+		WinTarget: b.winTarget,
+		Seed:      b.seed,
+		Spawn:     b.spawn,
+		State:     encodeSnapshot(b.snapshot()),
+		History: saveHistory{
+			Depth:     b.history.depth,
+			UndoLimit: b.history.undoLimit,
+			UndosUsed: b.history.undosUsed,
+			Undo:      encodeSnapshots(b.history.undo),
+			Redo:      encodeSnapshots(b.history.redo),
+		},
+	}
+	return json.Marshal(save)
+}
+
+func (b *board) UnmarshalBinary(data []byte) error {
+	save, err := decodeSave(data)
+	if err != nil {
+		return err
+	}
+	b.load(save)
+	return nil
+}
+
+//
+// internal methods
+//
+
+// decodeSave decodes and validates save data without touching the board
+func decodeSave(data []byte) (saveFile, error) {
+	var save saveFile
+	if err := json.Unmarshal(data, &save); err != nil {
+		return save, fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+	if save.Version < 1 || save.Version > SaveVersion {
+		return save, fmt.Errorf("%w: save version %d is not supported, expected version %d",
+			ErrIncompatibleSave, save.Version, SaveVersion)
+	}
+	if save.Version == 1 {
+		save.Spawn = EvenSpawns()
+	}
+	if err := save.validate(); err != nil {
+		return save, fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+	return save, nil
+}
+
+// load replaces the board state with a decoded save
+func (b *board) load(save saveFile) {
+	b.rows = save.Rows
+	b.cols = save.Cols
+	b.winTarget = save.WinTarget
+	b.spawn = save.Spawn.clone()
+	b.history = history{
+		depth:     save.History.Depth,
+		undoLimit: save.History.UndoLimit,
+		undosUsed: save.History.UndosUsed,
+		undo:      decodeSnapshots(save.History.Undo),
+		redo:      decodeSnapshots(save.History.Redo),
+	}
+	b.reseed(save.Seed)
+	b.restore(decodeSnapshot(save.State))
+}
+
+func (s saveFile) validate() error {
+	if s.Rows < _minBoardSize || s.Cols < _minBoardSize {
+		return fmt.Errorf("invalid board size %dx%d", s.Rows, s.Cols)
+	}
+	if !isPowerOfTwo(s.WinTarget) || s.WinTarget < 4 {
+		return fmt.Errorf("invalid win target %d", s.WinTarget)
+	}
+	if err := s.Spawn.Validate(); err != nil {
+		return fmt.Errorf("spawn policy: %w", err)
+	}
+	if s.History.Depth < 0 || s.History.UndosUsed < 0 {
+		return errors.New("invalid undo history settings")
+	}
+	if s.History.UndoLimit >= 0 && s.History.UndosUsed > s.History.UndoLimit {
+		return fmt.Errorf("%d undos used exceed the limit of %d", s.History.UndosUsed, s.History.UndoLimit)
+	}
+	if len(s.History.Undo) > s.History.Depth {
+		return errors.New("undo history exceeds its depth")
+	}
+	if len(s.History.Redo) > s.History.Depth {
+		return errors.New("redo history exceeds its depth")
+	}
+	if err := s.State.validate(s.Rows, s.Cols, s.Spawn); err != nil {
+		return fmt.Errorf("game state: %w", err)
+	}
+	for i, snapshot := range append(s.History.Undo, s.History.Redo...) {
+		if err := snapshot.validate(s.Rows, s.Cols, s.Spawn); err != nil {
+			return fmt.Errorf("history entry %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// _maxDrawsPerTile bounds the random draws made for each spawned tile, one for its cell and one for its value
+// with room for the rare retries of rand.Intn
+const _maxDrawsPerTile = 4
+
+func (s saveSnapshot) validate(rows, cols int, spawn SpawnPolicy) error {
+	if s.Cells.Rows() != rows {
+		return fmt.Errorf("expected %d rows, got %d", rows, s.Cells.Rows())
+	}
+	for rowIdx, row := range s.Cells {
+		if len(row) != cols {
+			return fmt.Errorf("expected %d columns in row %d, got %d", cols, rowIdx, len(row))
+		}
+		for colIdx, value := range row {
+			if value != _emptyCell && (value == 1 || !isPowerOfTwo(value)) {
+				return fmt.Errorf("invalid cell value %d at row %d column %d", value, rowIdx, colIdx)
+			}
+		}
+	}
+	if s.Moves < 0 {
+		return fmt.Errorf("invalid move count %d", s.Moves)
+	}
+	// every move spawns at least one tile and merges keep the sum of the cells, so the cells bound the number
+	// of moves, and the moves bound the draws the random source is rewound through on load
+	if uint64(s.Moves) > s.Cells.sum()/uint64(spawn.minValue()) {
+		return fmt.Errorf("%d moves could not have been made on the cells", s.Moves)
+	}
+	tiles := float64(minInt(spawn.Start, rows*cols)) + float64(s.Moves)*float64(minInt(spawn.PerMove, rows*cols))
+	if float64(s.Draws) > tiles*_maxDrawsPerTile {
+		return fmt.Errorf("%d random draws could not have been made in %d moves", s.Draws, s.Moves)
+	}
+	return nil
+}
+
+func encodeSnapshot(s snapshot) saveSnapshot {
+	return saveSnapshot{Cells: s.cells, Score: s.score, Won: s.won, Moves: s.moves, Draws: s.draws}
+}
+
+func decodeSnapshot(s saveSnapshot) snapshot {
+	return snapshot{cells: s.Cells.Clone(), score: s.Score, won: s.Won, moves: s.Moves, draws: s.Draws}
+}
+
+func encodeSnapshots(snapshots []snapshot) []saveSnapshot {
+	encoded := make([]saveSnapshot, len(snapshots))
+	for i, s := range snapshots {
+		encoded[i] = encodeSnapshot(s)
+	}
+	return encoded
+}
+
+func decodeSnapshots(snapshots []saveSnapshot) []snapshot {
+	decoded := make([]snapshot, len(snapshots))
+	for i, s := range snapshots {
+		decoded[i] = decodeSnapshot(s)
+	}
+	return decoded
+}
+
+func isPowerOfTwo(value uint32) bool {
+	return value != 0 && value&(value-1) == 0
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
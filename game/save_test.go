@@ -0,0 +1,88 @@
+package game
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBoard_saveAndLoad(t *testing.T) {
+	gc := NewController(WithSeed(5), WithSize(3, 4), WithUndoLimit(2), WithWinTarget(256), WithSpawnPolicy(EasySpawns()))
+	for _, direction := range []Direction{DirectionLeft, DirectionUp, DirectionRight, DirectionDown, DirectionLeft} {
+		gc.Shift(direction)
+	}
+	gc.Undo()
+
+	data, err := gc.MarshalBinary()
+	equal(t, nil, err)
+	loaded, err := LoadController(data)
+	equal(t, nil, err)
+
+	equal(t, gc.GetCells(), loaded.GetCells())
+	equal(t, gc.GetScore(), loaded.GetScore())
+	equal(t, gc.GetMoveCount(), loaded.GetMoveCount())
+	equal(t, gc.Seed(), loaded.Seed())
+	equal(t, gc.WinTarget(), loaded.WinTarget())
+	equal(t, gc.UndosRemaining(), loaded.UndosRemaining())
+	equal(t, gc.CanRedo(), loaded.CanRedo())
+	equal(t, gc.SpawnPolicy(), loaded.SpawnPolicy())
+
+	// the restored random source continues exactly where the saved game left off
+	for _, direction := range []Direction{DirectionDown, DirectionRight, DirectionUp, DirectionLeft} {
+		equal(t, gc.Shift(direction), loaded.Shift(direction))
+		equal(t, gc.GetCells(), loaded.GetCells())
+	}
+	equal(t, gc.Undo(), loaded.Undo())
+	equal(t, gc.GetCells(), loaded.GetCells())
+}
+
+func TestBoard_loadErrors(t *testing.T) {
+	gc := NewController(WithSeed(5))
+	valid, err := gc.MarshalBinary()
+	equal(t, nil, err)
+	undone := NewController(WithSeed(5))
+	for _, direction := range []Direction{DirectionLeft, DirectionRight, DirectionUp, DirectionDown} {
+		if undone.Shift(direction) {
+			break
+		}
+	}
+	undone.Undo()
+	withRedo, err := undone.MarshalBinary()
+	equal(t, nil, err)
+
+	tests := []struct {
+		name string
+		data string
+		err  error
+	}{
+		{"not json", "not a save", ErrCorruptSave},
+		{"truncated", string(valid[:len(valid)/2]), ErrCorruptSave},
+		{"future version", strings.Replace(string(valid), `"version":2`, `"version":99`, 1), ErrIncompatibleSave},
+		{"bad spawn policy", strings.Replace(string(valid), `"per_move":1`, `"per_move":0`, 1), ErrCorruptSave},
+		{"bad size", strings.Replace(string(valid), `"rows":4`, `"rows":1`, 1), ErrCorruptSave},
+		{"bad win target", strings.Replace(string(valid), `"win_target":2048`, `"win_target":2000`, 1), ErrCorruptSave},
+		{"bad cells", `{"version":1,"rows":2,"cols":2,"win_target":2048,"state":{"cells":[[3,0],[0,0]]}}`, ErrCorruptSave},
+		{"impossible draws", strings.Replace(string(valid), `"draws":4`, `"draws":18446744073709551615`, 1), ErrCorruptSave},
+		{"impossible moves", `{"version":2,"rows":2,"cols":2,"win_target":2048,"spawn":{"weights":[{"value":2,"weight":1}],"start":2,"per_move":1},"state":{"cells":[[2,2],[0,0]],"moves":3}}`, ErrCorruptSave},
+		{"undos over limit", strings.Replace(strings.Replace(string(valid), `"undo_limit":-1`, `"undo_limit":3`, 1), `"undos_used":0`, `"undos_used":5`, 1), ErrCorruptSave},
+		{"redo over depth", strings.Replace(string(withRedo), `"depth":100`, `"depth":0`, 1), ErrCorruptSave},
+		{"missing row", `{"version":1,"rows":2,"cols":2,"win_target":2048,"state":{"cells":[[2,0]]}}`, ErrCorruptSave},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := gc.GetCells()
+			err := gc.UnmarshalBinary([]byte(tt.data))
+			equal(t, true, errors.Is(err, tt.err))
+			equal(t, before, gc.GetCells())
+		})
+	}
+}
+
+func TestBoard_loadVersion1(t *testing.T) {
+	// saves written before spawn policies were configurable continue with the odds they were played with
+	data := `{"version":1,"rows":2,"cols":2,"win_target":2048,"seed":3,"state":{"cells":[[2,0],[0,4]]},"history":{"depth":1}}`
+	gc, err := LoadController([]byte(data), WithSpawnPolicy(HardSpawns()))
+	equal(t, nil, err)
+	equal(t, EvenSpawns(), gc.SpawnPolicy())
+	equal(t, Cells{{2, 0}, {0, 4}}, gc.GetCells())
+}
@@ -0,0 +1,157 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// SpawnWeight is a cell value the game can spawn and its chance of being chosen, relative to the weights of
+// the other values
+type SpawnWeight struct {
+	Value  uint32 `json:"value"`
+	Weight int    `json:"weight"`
+}
+
+// SpawnPolicy decides which tiles the game adds at the start and after every move
+type SpawnPolicy struct {
+	// Weights lists the values that can spawn, each tile's value is drawn from them
+	Weights []SpawnWeight `json:"weights"`
+	// Start is the number of tiles on a new board
+	Start int `json:"start"`
+	// PerMove is the number of tiles added after each move, fewer if the board fills up
+	PerMove int `json:"per_move"`
+}
+
+// ClassicSpawns spawns a 2 nine times out of ten and a 4 otherwise, one tile per move. It is the default.
+func ClassicSpawns() SpawnPolicy {
+	return SpawnPolicy{Weights: []SpawnWeight{{Value: 2, Weight: 9}, {Value: 4, Weight: 1}}, Start: 2, PerMove: 1}
+}
+
+// EvenSpawns spawns a 2 or a 4 with equal chance, one tile per move. Games saved or recorded before spawn
+// policies were configurable were played with it.
+func EvenSpawns() SpawnPolicy {
+	return SpawnPolicy{Weights: []SpawnWeight{{Value: 2, Weight: 1}, {Value: 4, Weight: 1}}, Start: 2, PerMove: 1}
+}
+
+// EasySpawns spawns larger tiles more often, including the occasional 8
+func EasySpawns() SpawnPolicy {
+	return SpawnPolicy{
+		Weights: []SpawnWeight{{Value: 2, Weight: 6}, {Value: 4, Weight: 3}, {Value: 8, Weight: 1}},
+		Start:   2,
+		PerMove: 1,
+	}
+}
+
+// HardSpawns uses the classic odds but adds two tiles after every move
+func HardSpawns() SpawnPolicy {
+	return SpawnPolicy{Weights: []SpawnWeight{{Value: 2, Weight: 9}, {Value: 4, Weight: 1}}, Start: 2, PerMove: 2}
+}
+
+var spawnPresets = map[string]func() SpawnPolicy{
+	"classic": ClassicSpawns,
+	"even":    EvenSpawns,
+	"easy":    EasySpawns,
+	"hard":    HardSpawns,
+}
+
+// SpawnPreset returns the spawn policy with the given name, one of SpawnPresets. False is returned if there
+// is no preset with the name.
+func SpawnPreset(name string) (SpawnPolicy, bool) {
+	preset, ok := spawnPresets[name]
+	if !ok {
+		return SpawnPolicy{}, false
+	}
+	return preset(), true
+}
+
+// SpawnPresets returns the names of the spawn policy presets in alphabetical order
+func SpawnPresets() []string {
+	names := make([]string, 0, len(spawnPresets))
+	for name := range spawnPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate returns an error if the policy cannot be used by a game: a value is not a power of two of at
+// least 2, a weight is not positive, or fewer than one tile is added at the start or after a move
+func (p SpawnPolicy) Validate() error {
+	if len(p.Weights) == 0 {
+		return errors.New("no spawn values")
+	}
+	for _, w := range p.Weights {
+		if w.Value < 2 || !isPowerOfTwo(w.Value) {
+			return fmt.Errorf("invalid spawn value %d", w.Value)
+		}
+		if w.Weight <= 0 {
+			return fmt.Errorf("invalid weight %d for spawn value %d", w.Weight, w.Value)
+		}
+	}
+	if p.Start < 1 || p.PerMove < 1 {
+		return fmt.Errorf("invalid tile counts, %d at the start and %d per move", p.Start, p.PerMove)
+	}
+	return nil
+}
+
+// Probability returns the chance of a spawned tile having the value
+func (p SpawnPolicy) Probability(value uint32) float64 {
+	var matching, total int
+	for _, w := range p.Weights {
+		total += w.Weight
+		if w.Value == value {
+			matching += w.Weight
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(matching) / float64(total)
+}
+
+// MaxValue returns the largest value the policy spawns
+func (p SpawnPolicy) MaxValue() uint32 {
+	var max uint32
+	for _, w := range p.Weights {
+		if w.Value > max {
+			max = w.Value
+		}
+	}
+	return max
+}
+
+// minValue returns the smallest value the policy spawns
+func (p SpawnPolicy) minValue() uint32 {
+	min := p.MaxValue()
+	for _, w := range p.Weights {
+		if w.Value < min {
+			min = w.Value
+		}
+	}
+	return min
+}
+
+// clone returns a copy of the policy that does not share its weights
+func (p SpawnPolicy) clone() SpawnPolicy {
+	p.Weights = append([]SpawnWeight(nil), p.Weights...)
+	return p
+}
+
+// draw picks a value with a single random draw, so EvenSpawns draws the same values as games did before
+// spawn policies were configurable
+func (p SpawnPolicy) draw(rng *rand.Rand) uint32 {
+	total := 0
+	for _, w := range p.Weights {
+		total += w.Weight
+	}
+	n := rng.Intn(total)
+	for _, w := range p.Weights {
+		if n < w.Weight {
+			return w.Value
+		}
+		n -= w.Weight
+	}
+	return p.Weights[len(p.Weights)-1].Value
+}
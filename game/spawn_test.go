@@ -0,0 +1,103 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSpawnPolicy_Validate(t *testing.T) {
+	for _, name := range SpawnPresets() {
+		policy, ok := SpawnPreset(name)
+		equal(t, true, ok)
+		equal(t, nil, policy.Validate())
+	}
+	_, ok := SpawnPreset("impossible")
+	equal(t, false, ok)
+
+	invalid := []SpawnPolicy{
+		{Start: 2, PerMove: 1},
+		{Weights: []SpawnWeight{{Value: 3, Weight: 1}}, Start: 2, PerMove: 1},
+		{Weights: []SpawnWeight{{Value: 1, Weight: 1}}, Start: 2, PerMove: 1},
+		{Weights: []SpawnWeight{{Value: 2, Weight: 0}}, Start: 2, PerMove: 1},
+		{Weights: []SpawnWeight{{Value: 2, Weight: 1}}, Start: 0, PerMove: 1},
+		{Weights: []SpawnWeight{{Value: 2, Weight: 1}}, Start: 2, PerMove: 0},
+	}
+	for _, policy := range invalid {
+		equal(t, true, policy.Validate() != nil)
+	}
+}
+
+func TestSpawnPolicy_Probability(t *testing.T) {
+	policy := ClassicSpawns()
+	equal(t, 0.9, policy.Probability(2))
+	equal(t, 0.1, policy.Probability(4))
+	equal(t, 0.0, policy.Probability(8))
+	equal(t, uint32(8), EasySpawns().MaxValue())
+}
+
+func TestSpawnPolicy_draw(t *testing.T) {
+	var (
+		policy = ClassicSpawns()
+		rng    = rand.New(rand.NewSource(1))
+		counts = map[uint32]int{}
+	)
+	for i := 0; i < 10000; i++ {
+		counts[policy.draw(rng)]++
+	}
+	equal(t, 2, len(counts))
+	equal(t, true, counts[4] > 900 && counts[4] < 1100)
+
+	// games played before spawn policies flipped a coin for every tile
+	var (
+		even   = EvenSpawns()
+		rng1   = rand.New(rand.NewSource(2))
+		rng2   = rand.New(rand.NewSource(2))
+		legacy = [2]uint32{2, 4}
+	)
+	for i := 0; i < 100; i++ {
+		equal(t, legacy[rng1.Intn(2)], even.draw(rng2))
+	}
+}
+
+func TestNewController_WithSpawnPolicy(t *testing.T) {
+	policy := SpawnPolicy{Weights: []SpawnWeight{{Value: 8, Weight: 1}}, Start: 3, PerMove: 2}
+	gc := NewController(WithSeed(1), WithSpawnPolicy(policy))
+	equal(t, policy, gc.SpawnPolicy())
+	equal(t, 13, len(gc.GetCells().EmptyPositions()))
+
+	res := gc.Move(DirectionLeft)
+	if !res.Changed {
+		res = gc.Move(DirectionRight)
+	}
+	equal(t, 2, len(res.Spawned))
+	for _, tile := range res.Spawned {
+		equal(t, uint32(8), tile.Value)
+	}
+
+	// the policy cannot be changed through the copy returned
+	gc.SpawnPolicy().Weights[0].Value = 2
+	equal(t, uint32(8), gc.SpawnPolicy().Weights[0].Value)
+}
+
+func TestNewController_WithSpawnPolicyBitboard(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		reference := NewController(WithSeed(seed), WithSpawnPolicy(HardSpawns()))
+		bitboard := NewController(WithSeed(seed), WithSpawnPolicy(HardSpawns()), WithBitboard())
+		for !reference.Lost() {
+			direction := directions[rng.Intn(len(directions))]
+			equal(t, reference.Shift(direction), bitboard.Shift(direction))
+			equal(t, reference.GetCells(), bitboard.GetCells())
+			if t.Failed() {
+				t.Fatalf("engines diverged with seed %d after %d moves", seed, reference.GetMoveCount())
+			}
+		}
+	}
+}
+
+func TestWithSpawnPolicy_invalid(t *testing.T) {
+	defer func() {
+		equal(t, "WithSpawnPolicy: no spawn values", recover())
+	}()
+	NewController(WithSpawnPolicy(SpawnPolicy{Start: 2, PerMove: 1}))
+}
@@ -3,8 +3,20 @@ module github.com/brandenc40/2048
 go 1.17
 
 require (
+	github.com/gdamore/tcell/v2 v2.4.0
+	github.com/gliderlabs/ssh v0.3.4
+	github.com/gorilla/websocket v1.5.0
 	github.com/mattn/go-runewidth v0.0.13
 	github.com/nsf/termbox-go v1.1.1
+	golang.org/x/crypto v0.0.0-20220525230936-793ad666bf5e
 )
 
-require github.com/rivo/uniseg v0.2.0 // indirect
+require (
+	github.com/anmitsu/go-shlex v0.0.0-20200514113438-38f4b401e2be // indirect
+	github.com/gdamore/encoding v1.0.0 // indirect
+	github.com/lucasb-eyer/go-colorful v1.0.3 // indirect
+	github.com/rivo/uniseg v0.2.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/term v0.0.0-20220526004731-065cf7ba2467 // indirect
+	golang.org/x/text v0.13.0 // indirect
+)
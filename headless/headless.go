@@ -0,0 +1,231 @@
+// Package headless plays a game over plain text streams, reading commands from an io.Reader and writing
+// the board after each one to an io.Writer. It lets scripts, pipes and bots written in any language drive
+// a game without a terminal.
+//
+// Each input line is a command. Text commands are a direction, "u", "d", "l" and "r" or "up", "down", "left"
+// and "right", or one of "undo", "redo", "reset", "board" and "quit". A line starting with "{" is read as a
+// JSON command such as {"action":"move","direction":"left"}, where the action is one of "move", "undo",
+// "redo", "reset", "board" and "quit".
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/brandenc40/2048/game"
+)
+
+// Format of the board written after each command
+type Format uint8
+
+const (
+	// FormatText writes the board as a grid of numbers followed by a blank line
+	FormatText Format = iota
+	// FormatJSON writes a State as a single line of JSON
+	FormatJSON
+)
+
+// State of the game written after each command in FormatJSON
+type State struct {
+	Cells      game.Cells `json:"cells"`
+	Score      uint64     `json:"score"`
+	Moves      int        `json:"moves"`
+	Won        bool       `json:"won"`
+	Lost       bool       `json:"lost"`
+	Changed    bool       `json:"changed"`
+	ScoreDelta uint64     `json:"score_delta"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// NewState builds the state of the game
+func NewState(gc game.Controller) State {
+	return State{
+		Cells: gc.GetCells(),
+		Score: gc.GetScore(),
+		Moves: gc.GetMoveCount(),
+		Won:   gc.Won(),
+		Lost:  gc.Lost(),
+	}
+}
+
+// Command is a JSON command read from the input
+type Command struct {
+	Action    string `json:"action"`
+	Direction string `json:"direction,omitempty"`
+}
+
+// Run plays the game until the input ends or a quit command is read. The board is written once before the
+// first command. Invalid commands are reported in the output and do not stop the game, only errors reading
+// the input or writing the output are returned.
+func Run(gc game.Controller, in io.Reader, out io.Writer, options ...Option) error {
+	s := session{gc: gc, out: bufio.NewWriter(out), format: FormatText}
+	for _, option := range options {
+		option.apply(&s)
+	}
+	if err := s.write(NewState(gc)); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		cmd, err := ParseCommand(line)
+		if err == nil && cmd.Action == "quit" {
+			return nil
+		}
+		var state State
+		if err == nil {
+			state, err = Apply(gc, cmd)
+		}
+		if err != nil {
+			state = NewState(gc)
+			state.Error = err.Error()
+		}
+		if err := s.write(state); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading commands: %w", err)
+	}
+	return nil
+}
+
+type session struct {
+	gc     game.Controller
+	out    *bufio.Writer
+	format Format
+}
+
+// ParseCommand parses an input line, either a text command or a JSON command
+func ParseCommand(line string) (Command, error) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "{") {
+		var cmd Command
+		if err := json.Unmarshal([]byte(line), &cmd); err != nil {
+			return Command{}, fmt.Errorf("invalid JSON command: %v", err)
+		}
+		return cmd, nil
+	}
+	if action := strings.ToLower(line); isAction(action) {
+		return Command{Action: action}, nil
+	}
+	if _, ok := ParseDirection(line); !ok {
+		return Command{}, fmt.Errorf("unknown command %q", line)
+	}
+	return Command{Action: "move", Direction: line}, nil
+}
+
+// Apply runs a command other than quit on the game, returning the state of the game afterwards
+func Apply(gc game.Controller, cmd Command) (State, error) {
+	var state State
+	switch cmd.Action {
+	case "move":
+		direction, ok := ParseDirection(cmd.Direction)
+		if !ok {
+			return State{}, fmt.Errorf("unknown direction %q", cmd.Direction)
+		}
+		res := gc.Move(direction)
+		state = NewState(gc)
+		state.Changed = res.Changed
+		state.ScoreDelta = res.ScoreDelta
+	case "undo":
+		changed := gc.Undo()
+		state = NewState(gc)
+		state.Changed = changed
+	case "redo":
+		changed := gc.Redo()
+		state = NewState(gc)
+		state.Changed = changed
+	case "reset":
+		gc.Reset()
+		state = NewState(gc)
+		state.Changed = true
+	case "board":
+		state = NewState(gc)
+	default:
+		return State{}, fmt.Errorf("unknown action %q", cmd.Action)
+	}
+	return state, nil
+}
+
+func isAction(action string) bool {
+	switch action {
+	case "undo", "redo", "reset", "board", "quit":
+		return true
+	}
+	return false
+}
+
+// ParseDirection parses a direction name, either the full name such as "left" or its first letter
+func ParseDirection(name string) (game.Direction, bool) {
+	switch strings.ToLower(name) {
+	case "l", "left":
+		return game.DirectionLeft, true
+	case "u", "up":
+		return game.DirectionUp, true
+	case "r", "right":
+		return game.DirectionRight, true
+	case "d", "down":
+		return game.DirectionDown, true
+	}
+	return 0, false
+}
+
+func (s *session) write(state State) error {
+	var err error
+	switch s.format {
+	case FormatJSON:
+		err = json.NewEncoder(s.out).Encode(state)
+	default:
+		_, err = s.out.WriteString(FormatBoard(state))
+	}
+	if err == nil {
+		err = s.out.Flush()
+	}
+	if err != nil {
+		return fmt.Errorf("writing board: %w", err)
+	}
+	return nil
+}
+
+// FormatBoard formats the state as text, with the score and move count above a grid of the cell values
+// and any error or game over message below it
+func FormatBoard(state State) string {
+	var (
+		sb    strings.Builder
+		width = len(fmt.Sprint(state.Cells.MaxValue()))
+	)
+	if width < 4 {
+		width = 4
+	}
+	fmt.Fprintf(&sb, "Score: %d  Moves: %d\n", state.Score, state.Moves)
+	for _, row := range state.Cells {
+		for colIdx, value := range row {
+			if colIdx > 0 {
+				sb.WriteByte(' ')
+			}
+			cell := "."
+			if value != 0 {
+				cell = fmt.Sprint(value)
+			}
+			fmt.Fprintf(&sb, "%*s", width, cell)
+		}
+		sb.WriteByte('\n')
+	}
+	switch {
+	case state.Error != "":
+		fmt.Fprintf(&sb, "error: %s\n", state.Error)
+	case state.Lost:
+		sb.WriteString("No more moves, game over\n")
+	case state.Won:
+		sb.WriteString("You win!\n")
+	}
+	sb.WriteByte('\n')
+	return sb.String()
+}
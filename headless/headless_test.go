@@ -0,0 +1,84 @@
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/brandenc40/2048/game"
+)
+
+func TestRun_text(t *testing.T) {
+	// both moves change this board with the seed and even spawns
+	gc := game.NewController(game.WithSeed(1), game.WithSize(2, 3), game.WithSpawnPolicy(game.EvenSpawns()))
+	var out strings.Builder
+	err := Run(gc, strings.NewReader("l\n\nUP\nundo\nfly\nquit\nr\n"), &out)
+	equal(t, nil, err)
+
+	boards := strings.Split(strings.TrimSuffix(out.String(), "\n\n"), "\n\n")
+	// the initial board, two moves, undo and the invalid command; nothing after quit
+	equal(t, 5, len(boards))
+	equal(t, 3, len(strings.Split(boards[0], "\n")))
+	equal(t, true, strings.HasPrefix(boards[0], "Score: 0  Moves: 0\n"))
+	equal(t, true, strings.HasSuffix(boards[4], `error: unknown command "fly"`))
+	equal(t, 1, gc.GetMoveCount())
+}
+
+func TestRun_json(t *testing.T) {
+	gc := game.NewController(game.WithSeed(1))
+	reference := game.NewController(game.WithSeed(1))
+	in := strings.Join([]string{
+		`{"action":"move","direction":"left"}`,
+		`{"action":"move","direction":"up"}`,
+		`down`,
+		`{"action":"jump"}`,
+		`{"action":`,
+		`{"action":"board"}`,
+	}, "\n")
+	var out strings.Builder
+	equal(t, nil, Run(gc, strings.NewReader(in), &out, WithFormat(FormatJSON)))
+
+	var states []State
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var state State
+		equal(t, nil, json.Unmarshal(scanner.Bytes(), &state))
+		states = append(states, state)
+	}
+	equal(t, 7, len(states))
+	equal(t, reference.GetCells(), states[0].Cells)
+
+	for i, direction := range []game.Direction{game.DirectionLeft, game.DirectionUp, game.DirectionDown} {
+		res := reference.Move(direction)
+		equal(t, reference.GetCells(), states[i+1].Cells)
+		equal(t, res.Changed, states[i+1].Changed)
+		equal(t, res.ScoreDelta, states[i+1].ScoreDelta)
+		equal(t, reference.GetScore(), states[i+1].Score)
+	}
+	equal(t, `unknown action "jump"`, states[4].Error)
+	equal(t, true, strings.HasPrefix(states[5].Error, "invalid JSON command"))
+	equal(t, "", states[6].Error)
+	equal(t, reference.GetCells(), states[6].Cells)
+}
+
+func TestFormatBoard(t *testing.T) {
+	board := FormatBoard(State{
+		Cells: game.Cells{{2, 0}, {16384, 4}},
+		Score: 20,
+		Moves: 3,
+		Lost:  true,
+	})
+	equal(t, "Score: 20  Moves: 3\n"+
+		"    2     .\n"+
+		"16384     4\n"+
+		"No more moves, game over\n\n", board)
+}
+
+func equal(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
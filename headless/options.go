@@ -0,0 +1,22 @@
+package headless
+
+// Option configures a headless game run by Run
+type Option interface {
+	apply(s *session)
+}
+
+// WithFormat sets the format the board is written in, FormatText by default
+func WithFormat(format Format) Option {
+	return formatOption{format: format}
+}
+
+type formatOption struct {
+	format Format
+}
+
+func (o formatOption) apply(s *session) {
+	if o.format != FormatText && o.format != FormatJSON {
+		panic("WithFormat: invalid format")
+	}
+	s.format = o.format
+}
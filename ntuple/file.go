@@ -0,0 +1,141 @@
+package ntuple
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// WeightsVersion is the version of the weights format written by Network.WriteTo
+const WeightsVersion = 1
+
+// weightsMagic starts every weights file, followed by a JSON header line and the weights as little endian
+// float32 values, pattern by pattern
+const weightsMagic = "2048 n-tuple weights\n"
+
+var (
+	// ErrCorruptWeights is returned when weights cannot be decoded
+	ErrCorruptWeights = errors.New("corrupt n-tuple weights")
+	// ErrIncompatibleWeights is returned when weights were written with an unsupported format version
+	ErrIncompatibleWeights = errors.New("incompatible n-tuple weights")
+)
+
+type weightsHeader struct {
+	Version  int       `json:"version"`
+	Games    int64     `json:"games"`
+	Patterns []Pattern `json:"patterns"`
+}
+
+// WriteTo writes the patterns and weights of the network along with the number of games it was trained on
+func (n *Network) WriteTo(w io.Writer) (int64, error) {
+	header, err := json.Marshal(weightsHeader{Version: WeightsVersion, Games: n.games, Patterns: n.patterns})
+	if err != nil {
+		return 0, err
+	}
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	bw.WriteString(weightsMagic)
+	bw.Write(header)
+	bw.WriteByte('\n')
+	for _, weights := range n.weights {
+		if err := binary.Write(bw, binary.LittleEndian, weights); err != nil {
+			return cw.n, err
+		}
+	}
+	err = bw.Flush()
+	return cw.n, err
+}
+
+// ReadNetwork reads a network written by Network.WriteTo. An error wrapping ErrCorruptWeights or
+// ErrIncompatibleWeights is returned if the data cannot be read.
+func ReadNetwork(r io.Reader) (*Network, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(weightsMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != weightsMagic {
+		return nil, fmt.Errorf("%w: not an n-tuple weights file", ErrCorruptWeights)
+	}
+	line, err := br.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptWeights, err)
+	}
+	var header weightsHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptWeights, err)
+	}
+	if header.Version < 1 || header.Version > WeightsVersion {
+		return nil, fmt.Errorf("%w: weights version %d is not supported, expected version %d",
+			ErrIncompatibleWeights, header.Version, WeightsVersion)
+	}
+	if len(header.Patterns) == 0 || header.Games < 0 {
+		return nil, fmt.Errorf("%w: invalid header", ErrCorruptWeights)
+	}
+	n, err := NewNetwork(header.Patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptWeights, err)
+	}
+	n.games = header.Games
+	for i, weights := range n.weights {
+		if err := binary.Read(br, binary.LittleEndian, weights); err != nil {
+			return nil, fmt.Errorf("%w: weights of pattern %d: %v", ErrCorruptWeights, i, err)
+		}
+	}
+	if _, err := br.ReadByte(); err != io.EOF {
+		return nil, fmt.Errorf("%w: unexpected data after the weights", ErrCorruptWeights)
+	}
+	return n, nil
+}
+
+// Save writes the network to a file, replacing it only once the write has succeeded
+func (n *Network) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating weights directory: %w", err)
+	}
+	// write to a temporary file first so a failed write never corrupts the previous weights
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("writing weights: %w", err)
+	}
+	if _, err := n.WriteTo(f); err != nil {
+		f.Close()
+		return fmt.Errorf("writing weights: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing weights: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("writing weights: %w", err)
+	}
+	return nil
+}
+
+// Load reads a network saved to a file by Network.Save
+func Load(path string) (*Network, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading weights: %w", err)
+	}
+	defer f.Close()
+	n, err := ReadNetwork(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading weights %s: %w", path, err)
+	}
+	return n, nil
+}
+
+// countingWriter counts the bytes written through it for WriteTo
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,60 @@
+package ntuple
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNetwork_WriteTo(t *testing.T) {
+	n, err := NewNetwork()
+	equal(t, nil, err)
+	equal(t, nil, NewTrainer(n).Train(context.Background(), 5))
+
+	var buf bytes.Buffer
+	written, err := n.WriteTo(&buf)
+	equal(t, nil, err)
+	equal(t, int64(buf.Len()), written)
+
+	read, err := ReadNetwork(bytes.NewReader(buf.Bytes()))
+	equal(t, nil, err)
+	equal(t, n, read)
+	equal(t, int64(5), read.Games())
+
+	data := buf.Bytes()
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", nil, ErrCorruptWeights},
+		{"not weights", []byte(`{"version": 1}`), ErrCorruptWeights},
+		{"truncated", data[:len(data)-1], ErrCorruptWeights},
+		{"trailing data", append(append([]byte(nil), data...), 0), ErrCorruptWeights},
+		{"newer version", []byte(weightsMagic + `{"version": 2, "patterns": [[{"Row": 0, "Col": 0}]]}` + "\n"), ErrIncompatibleWeights},
+		{"bad pattern", []byte(weightsMagic + `{"version": 1, "patterns": [[{"Row": 9, "Col": 0}]]}` + "\n"), ErrCorruptWeights},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadNetwork(bytes.NewReader(tt.data))
+			equal(t, true, errors.Is(err, tt.err))
+		})
+	}
+}
+
+func TestNetwork_Save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weights", "ntuple.weights")
+	n, err := NewNetwork(Pattern{{Row: 0, Col: 0}, {Row: 0, Col: 1}})
+	equal(t, nil, err)
+	equal(t, nil, n.Save(path))
+
+	loaded, err := Load(path)
+	equal(t, nil, err)
+	equal(t, n, loaded)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.weights"))
+	equal(t, true, err != nil && strings.HasPrefix(err.Error(), "reading weights"))
+}
@@ -0,0 +1,162 @@
+// Package ntuple learns to evaluate 4x4 game boards with an n-tuple network, trained by temporal difference
+// learning over games played against itself. Training runs on the CPU and is reproducible from its seed.
+package ntuple
+
+import (
+	"fmt"
+
+	"github.com/brandenc40/2048/game"
+)
+
+const (
+	boardSize = 4
+	// maxTupleSize bounds the cells in a pattern, each extra cell multiplies its weights by 16
+	maxTupleSize = 6
+	// symmetries is the number of rotations and reflections of the board
+	symmetries = 8
+)
+
+// Pattern is a tuple of cells on a 4x4 board. The network holds a weight for every combination of tiles the
+// cells can hold, shared by the pattern's rotations and reflections.
+type Pattern []game.Position
+
+// DefaultPatterns are the outer and inner lines and three 2x2 squares, which learn quickly and need about a
+// megabyte of weights
+func DefaultPatterns() []Pattern {
+	return []Pattern{
+		{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 0, Col: 2}, {Row: 0, Col: 3}},
+		{{Row: 1, Col: 0}, {Row: 1, Col: 1}, {Row: 1, Col: 2}, {Row: 1, Col: 3}},
+		{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 1, Col: 0}, {Row: 1, Col: 1}},
+		{{Row: 0, Col: 1}, {Row: 0, Col: 2}, {Row: 1, Col: 1}, {Row: 1, Col: 2}},
+		{{Row: 1, Col: 1}, {Row: 1, Col: 2}, {Row: 2, Col: 1}, {Row: 2, Col: 2}},
+	}
+}
+
+// LargePatterns are four 6-tuples that play much stronger once trained, at the cost of 256 megabytes of
+// weights and far longer training
+func LargePatterns() []Pattern {
+	return []Pattern{
+		{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 0, Col: 2}, {Row: 0, Col: 3}, {Row: 1, Col: 0}, {Row: 1, Col: 1}},
+		{{Row: 1, Col: 0}, {Row: 1, Col: 1}, {Row: 1, Col: 2}, {Row: 1, Col: 3}, {Row: 2, Col: 0}, {Row: 2, Col: 1}},
+		{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 0, Col: 2}, {Row: 1, Col: 0}, {Row: 1, Col: 1}, {Row: 1, Col: 2}},
+		{{Row: 1, Col: 0}, {Row: 1, Col: 1}, {Row: 1, Col: 2}, {Row: 2, Col: 0}, {Row: 2, Col: 1}, {Row: 2, Col: 2}},
+	}
+}
+
+// Validate returns an error if the pattern is empty, has more than 6 cells, or has a cell that is off the
+// board or repeated
+func (p Pattern) Validate() error {
+	if len(p) == 0 || len(p) > maxTupleSize {
+		return fmt.Errorf("patterns must have 1 to %d cells, got %d", maxTupleSize, len(p))
+	}
+	seen := map[game.Position]bool{}
+	for _, pos := range p {
+		if pos.Row < 0 || pos.Row >= boardSize || pos.Col < 0 || pos.Col >= boardSize {
+			return fmt.Errorf("cell at row %d column %d is off the board", pos.Row, pos.Col)
+		}
+		if seen[pos] {
+			return fmt.Errorf("cell at row %d column %d is repeated", pos.Row, pos.Col)
+		}
+		seen[pos] = true
+	}
+	return nil
+}
+
+// Network scores 4x4 boards by summing a weight for each pattern in each of its symmetric placements. The
+// score estimates the points still to be earned from the board. A Network is not safe for concurrent use
+// while it is being trained.
+type Network struct {
+	patterns []Pattern
+	// placements holds the cells of every symmetric placement of each pattern
+	placements [][symmetries]Pattern
+	weights    [][]float32
+	// games is the number of training games the weights have learned from
+	games int64
+}
+
+// NewNetwork builds a network with every weight zero. DefaultPatterns are used if none are given.
+func NewNetwork(patterns ...Pattern) (*Network, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultPatterns()
+	}
+	n := &Network{}
+	for i, pattern := range patterns {
+		if err := pattern.Validate(); err != nil {
+			return nil, fmt.Errorf("pattern %d: %w", i, err)
+		}
+		n.patterns = append(n.patterns, append(Pattern(nil), pattern...))
+		n.placements = append(n.placements, placements(pattern))
+		n.weights = append(n.weights, make([]float32, 1<<(4*len(pattern))))
+	}
+	return n, nil
+}
+
+// Patterns returns a copy of the network's patterns
+func (n *Network) Patterns() []Pattern {
+	patterns := make([]Pattern, len(n.patterns))
+	for i, pattern := range n.patterns {
+		patterns[i] = append(Pattern(nil), pattern...)
+	}
+	return patterns
+}
+
+// Games returns the number of training games the network has learned from
+func (n *Network) Games() int64 { return n.games }
+
+// Value returns the estimated points still to be earned from the board
+func (n *Network) Value(b game.Bitboard) float64 {
+	var value float64
+	for i, placements := range n.placements {
+		for _, cells := range placements {
+			value += float64(n.weights[i][index(b, cells)])
+		}
+	}
+	return value
+}
+
+// update moves the value of the board towards the target, sharing the correction between every weight that
+// contributed to it. The updated value is returned.
+func (n *Network) update(b game.Bitboard, target, rate float64) float64 {
+	delta := float32(rate * (target - n.Value(b)) / float64(len(n.placements)*symmetries))
+	for i, placements := range n.placements {
+		for _, cells := range placements {
+			n.weights[i][index(b, cells)] += delta
+		}
+	}
+	return n.Value(b)
+}
+
+// index packs the exponents of the cells into the position of their weight
+func index(b game.Bitboard, cells Pattern) int {
+	idx := 0
+	for _, pos := range cells {
+		idx = idx<<4 | int(b.Exponent(pos.Row, pos.Col))
+	}
+	return idx
+}
+
+// placements returns the pattern in every rotation of the board and every rotation of its mirror image
+func placements(pattern Pattern) [symmetries]Pattern {
+	var all [symmetries]Pattern
+	current := append(Pattern(nil), pattern...)
+	for i := 0; i < symmetries; i++ {
+		if i == symmetries/2 {
+			current = transform(pattern, func(pos game.Position) game.Position {
+				return game.Position{Row: pos.Row, Col: boardSize - 1 - pos.Col}
+			})
+		}
+		all[i] = current
+		current = transform(current, func(pos game.Position) game.Position {
+			return game.Position{Row: pos.Col, Col: boardSize - 1 - pos.Row}
+		})
+	}
+	return all
+}
+
+func transform(pattern Pattern, f func(game.Position) game.Position) Pattern {
+	transformed := make(Pattern, len(pattern))
+	for i, pos := range pattern {
+		transformed[i] = f(pos)
+	}
+	return transformed
+}
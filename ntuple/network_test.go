@@ -0,0 +1,74 @@
+package ntuple
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/brandenc40/2048/game"
+)
+
+func TestPattern_Validate(t *testing.T) {
+	for _, pattern := range append(DefaultPatterns(), LargePatterns()...) {
+		equal(t, nil, pattern.Validate())
+	}
+	invalid := []Pattern{
+		{},
+		{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 0, Col: 2}, {Row: 0, Col: 3}, {Row: 1, Col: 0}, {Row: 1, Col: 1}, {Row: 1, Col: 2}},
+		{{Row: 0, Col: 4}},
+		{{Row: 1, Col: 1}, {Row: 1, Col: 1}},
+	}
+	for _, pattern := range invalid {
+		equal(t, true, pattern.Validate() != nil)
+	}
+	_, err := NewNetwork(Pattern{{Row: -1, Col: 0}})
+	equal(t, "pattern 0: cell at row -1 column 0 is off the board", err.Error())
+}
+
+func TestPlacements(t *testing.T) {
+	// a corner cell visits every corner, once rotated and once mirrored
+	corners := map[game.Position]int{}
+	for _, placement := range placements(Pattern{{Row: 0, Col: 0}}) {
+		corners[placement[0]]++
+	}
+	equal(t, map[game.Position]int{{Row: 0, Col: 0}: 2, {Row: 0, Col: 3}: 2, {Row: 3, Col: 0}: 2, {Row: 3, Col: 3}: 2}, corners)
+
+	// an outer line covers every edge in both directions
+	edges := map[[2]game.Position]bool{}
+	for _, placement := range placements(DefaultPatterns()[0]) {
+		edges[[2]game.Position{placement[0], placement[3]}] = true
+	}
+	equal(t, 8, len(edges))
+}
+
+func TestNetwork_update(t *testing.T) {
+	n, err := NewNetwork()
+	equal(t, nil, err)
+	b, _ := game.BitboardFromCells(game.Cells{
+		{2, 4, 8, 16},
+		{0, 0, 0, 32},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	equal(t, 0.0, n.Value(b))
+
+	// repeated updates settle on the target and every symmetry of the board shares its value
+	var value float64
+	for i := 0; i < 50; i++ {
+		value = n.update(b, 100, 0.1)
+	}
+	equal(t, true, value > 99.9 && value < 100.1)
+	mirrored, _ := game.BitboardFromCells(game.Cells{
+		{16, 8, 4, 2},
+		{32, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	equal(t, value, n.Value(mirrored))
+}
+
+func equal(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
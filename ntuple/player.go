@@ -0,0 +1,46 @@
+package ntuple
+
+import (
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/solver"
+)
+
+// Player chooses the move whose points plus the network's value of the resulting board is highest
+type Player struct {
+	network *Network
+}
+
+var _ solver.Strategy = (*Player)(nil)
+
+// NewPlayer builds a player that picks moves with the network. The network must not be trained while the
+// player is in use.
+func NewPlayer(network *Network) *Player {
+	return &Player{network: network}
+}
+
+// NextMove returns the best move for the board. False is returned if no move changes the board, or the board
+// is not 4x4 or holds a tile above 32768.
+func (p *Player) NextMove(cells game.Cells) (game.Direction, bool) {
+	b, ok := game.BitboardFromCells(cells)
+	if !ok {
+		return 0, false
+	}
+	direction, _, _, ok := bestMove(p.network, b)
+	return direction, ok
+}
+
+// bestMove returns the move with the highest points plus value of its afterstate, along with the afterstate
+// and points. False is returned if no move changes the board.
+func bestMove(network *Network, b game.Bitboard) (direction game.Direction, afterstate game.Bitboard, reward uint32, ok bool) {
+	var best float64
+	for _, d := range solver.Directions {
+		shifted, score := b.Shift(d)
+		if shifted == b {
+			continue
+		}
+		if value := float64(score) + network.Value(shifted); !ok || value > best {
+			direction, afterstate, reward, best, ok = d, shifted, score, value, true
+		}
+	}
+	return direction, afterstate, reward, ok
+}
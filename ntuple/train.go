@@ -0,0 +1,284 @@
+package ntuple
+
+import (
+	"context"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+const (
+	defaultLearningRate = 0.1
+	defaultSeed         = 1
+)
+
+// Trainer improves a network by temporal difference learning on the boards left after each move, known as
+// afterstates, over games it plays against itself with the network choosing every move
+type Trainer struct {
+	network         *Network
+	rate            float64
+	lambda          float64
+	seed            int64
+	spawn           game.SpawnPolicy
+	progressEvery   int
+	progress        func(Progress)
+	checkpointPath  string
+	checkpointEvery int
+}
+
+// Progress summarises the training games played since the previous report
+type Progress struct {
+	// Games is the total number of games the network has learned from
+	Games int64
+	// Window is the number of games summarised
+	Window int
+	// AverageScore is the mean score of the games
+	AverageScore float64
+	// BestScore is the highest score of the games
+	BestScore uint64
+	// AverageMoves is the mean number of moves in a game
+	AverageMoves float64
+	// WinRate is the fraction of the games that reached 2048
+	WinRate float64
+	// HighestTile is the largest tile reached in any of the games
+	HighestTile uint32
+	// GamesPerSecond is the rate the games were played at
+	GamesPerSecond float64
+}
+
+// NewTrainer builds a trainer for the network. By default it learns with TD(0), a learning rate of 0.1 and
+// game.ClassicSpawns, seeded with 1.
+func NewTrainer(network *Network, options ...TrainerOption) *Trainer {
+	t := &Trainer{
+		network: network,
+		rate:    defaultLearningRate,
+		seed:    defaultSeed,
+		spawn:   game.ClassicSpawns(),
+	}
+	for _, option := range options {
+		option.apply(t)
+	}
+	return t
+}
+
+// Train plays the given number of games, learning from each one as it ends. Every game is seeded from the
+// trainer's seed and the number of games the network has already learned from, so training from a checkpoint
+// continues exactly as if it had never stopped. Training stops early once ctx is done, returning its error
+// after the checkpoint is written.
+func (t *Trainer) Train(ctx context.Context, games int) error {
+	var (
+		window  Progress
+		started = time.Now()
+		err     error
+	)
+	for i := 0; i < games; i++ {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		gc := t.playGame(gameSeed(t.seed, t.network.games))
+		t.network.games++
+
+		window.Window++
+		window.AverageScore += float64(gc.GetScore())
+		window.AverageMoves += float64(gc.GetMoveCount())
+		if gc.GetScore() > window.BestScore {
+			window.BestScore = gc.GetScore()
+		}
+		if gc.Won() {
+			window.WinRate++
+		}
+		if tile := gc.GetCells().MaxValue(); tile > window.HighestTile {
+			window.HighestTile = tile
+		}
+		if t.progress != nil && window.Window == t.progressEvery {
+			t.report(window, started)
+			window, started = Progress{}, time.Now()
+		}
+		if t.checkpointPath != "" && t.checkpointEvery > 0 && t.network.games%int64(t.checkpointEvery) == 0 {
+			if err := t.network.Save(t.checkpointPath); err != nil {
+				return err
+			}
+		}
+	}
+	if t.progress != nil && window.Window > 0 {
+		t.report(window, started)
+	}
+	if t.checkpointPath != "" {
+		if err := t.network.Save(t.checkpointPath); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// report turns the totals gathered in the window into averages and passes them to the progress function
+func (t *Trainer) report(window Progress, started time.Time) {
+	games := float64(window.Window)
+	window.Games = t.network.games
+	window.AverageScore /= games
+	window.AverageMoves /= games
+	window.WinRate /= games
+	if elapsed := time.Since(started).Seconds(); elapsed > 0 {
+		window.GamesPerSecond = games / elapsed
+	}
+	t.progress(window)
+}
+
+// step is an afterstate reached during a game and the points earned by the move that reached it
+type step struct {
+	afterstate game.Bitboard
+	reward     float64
+}
+
+// playGame plays a game on the bitboard engine, choosing every move with the network, then learns from it
+func (t *Trainer) playGame(seed int64) game.Controller {
+	gc := game.NewController(
+		game.WithBitboard(),
+		game.WithSeed(seed),
+		game.WithHistoryDepth(0),
+		game.WithSpawnPolicy(t.spawn),
+	)
+	var path []step
+	for {
+		b, _ := game.BitboardFromCells(gc.GetCells())
+		direction, afterstate, reward, ok := bestMove(t.network, b)
+		if !ok {
+			break
+		}
+		path = append(path, step{afterstate: afterstate, reward: float64(reward)})
+		gc.Shift(direction)
+	}
+	t.learn(path)
+	return gc
+}
+
+// learn walks the game backwards, moving the value of each afterstate towards its λ-return: the points of the
+// next move plus a blend of the next afterstate's value and its own λ-return. The game has no value left
+// after its final afterstate. With λ of 0 this is TD(0), with each target using the value already updated.
+func (t *Trainer) learn(path []step) {
+	var nextReward, nextValue, nextReturn float64
+	for i := len(path) - 1; i >= 0; i-- {
+		target := nextReward + (1-t.lambda)*nextValue + t.lambda*nextReturn
+		nextValue = t.network.update(path[i].afterstate, target, t.rate)
+		nextReturn, nextReward = target, path[i].reward
+	}
+}
+
+// gameSeed mixes the training seed with the index of a game using the splitmix64 finaliser, so neighbouring
+// games get unrelated seeds
+func gameSeed(seed, index int64) int64 {
+	z := uint64(seed) + uint64(index+1)*0x9E3779B97F4A7C15
+	z = (z ^ z>>30) * 0xBF58476D1CE4E5B9
+	z = (z ^ z>>27) * 0x94D049BB133111EB
+	return int64(z ^ z>>31)
+}
+
+// TrainerOption configures a Trainer
+type TrainerOption interface {
+	apply(t *Trainer)
+}
+
+// WithLearningRate sets how far each update moves a board's value towards its target, shared between the
+// weights of every pattern placement. The rate must be greater than 0 and at most 1, the default is 0.1.
+func WithLearningRate(rate float64) TrainerOption {
+	return learningRateOption{rate: rate}
+}
+
+type learningRateOption struct {
+	rate float64
+}
+
+func (o learningRateOption) apply(t *Trainer) {
+	if o.rate <= 0 || o.rate > 1 {
+		panic("WithLearningRate: rate must be greater than 0 and at most 1")
+	}
+	t.rate = o.rate
+}
+
+// WithLambda sets λ of TD(λ), which blends the values of all later afterstates of the game into each target.
+// λ must be between 0 and 1, the default of 0 learns with TD(0).
+func WithLambda(lambda float64) TrainerOption {
+	return lambdaOption{lambda: lambda}
+}
+
+type lambdaOption struct {
+	lambda float64
+}
+
+func (o lambdaOption) apply(t *Trainer) {
+	if o.lambda < 0 || o.lambda > 1 {
+		panic("WithLambda: lambda must be between 0 and 1")
+	}
+	t.lambda = o.lambda
+}
+
+// WithSeed sets the seed the training games are derived from. Training the same network with the same seed
+// and options always learns the same weights.
+func WithSeed(seed int64) TrainerOption {
+	return seedOption{seed: seed}
+}
+
+type seedOption struct {
+	seed int64
+}
+
+func (o seedOption) apply(t *Trainer) {
+	t.seed = o.seed
+}
+
+// WithSpawnPolicy sets the tiles added to the boards of the training games. The policy must pass
+// game.SpawnPolicy.Validate and only spawn tiles up to 32768.
+func WithSpawnPolicy(policy game.SpawnPolicy) TrainerOption {
+	return spawnPolicyOption{policy: policy}
+}
+
+type spawnPolicyOption struct {
+	policy game.SpawnPolicy
+}
+
+func (o spawnPolicyOption) apply(t *Trainer) {
+	if err := o.policy.Validate(); err != nil {
+		panic("WithSpawnPolicy: " + err.Error())
+	}
+	if o.policy.MaxValue() > 1<<15 {
+		panic("WithSpawnPolicy: spawn values must be at most 32768")
+	}
+	t.spawn = o.policy
+}
+
+// WithProgress calls report with a summary of every batch of games, and of any games left over at the end
+func WithProgress(every int, report func(Progress)) TrainerOption {
+	return progressOption{every: every, report: report}
+}
+
+type progressOption struct {
+	every  int
+	report func(Progress)
+}
+
+func (o progressOption) apply(t *Trainer) {
+	if o.every < 1 || o.report == nil {
+		panic("WithProgress: every must be at least 1 and report must not be nil")
+	}
+	t.progressEvery = o.every
+	t.progress = o.report
+}
+
+// WithCheckpoint saves the network to path every given number of games and when training stops. The
+// network is only saved when training stops if every is 0.
+func WithCheckpoint(path string, every int) TrainerOption {
+	return checkpointOption{path: path, every: every}
+}
+
+type checkpointOption struct {
+	path  string
+	every int
+}
+
+func (o checkpointOption) apply(t *Trainer) {
+	if o.path == "" || o.every < 0 {
+		panic("WithCheckpoint: path must not be empty and every must not be negative")
+	}
+	t.checkpointPath = o.path
+	t.checkpointEvery = o.every
+}
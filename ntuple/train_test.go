@@ -0,0 +1,111 @@
+package ntuple
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/brandenc40/2048/game"
+)
+
+func TestTrainer_Train(t *testing.T) {
+	var reports []Progress
+	n, _ := NewNetwork()
+	trainer := NewTrainer(n, WithSeed(3), WithProgress(100, func(p Progress) { reports = append(reports, p) }))
+	equal(t, nil, trainer.Train(context.Background(), 250))
+
+	equal(t, int64(250), n.Games())
+	equal(t, 3, len(reports))
+	equal(t, int64(100), reports[0].Games)
+	equal(t, int64(250), reports[2].Games)
+	equal(t, 50, reports[2].Window)
+	for _, p := range reports {
+		equal(t, true, p.AverageScore > 0 && float64(p.BestScore) >= p.AverageScore)
+		equal(t, true, p.AverageMoves > 0 && p.HighestTile >= 64)
+	}
+	// the network learns to score far more than it did in its first games
+	equal(t, true, reports[2].AverageScore > 1.5*reports[0].AverageScore)
+}
+
+func TestTrainer_reproducible(t *testing.T) {
+	train := func(seed int64, lambda float64, games ...int) *Network {
+		n, _ := NewNetwork()
+		for _, g := range games {
+			equal(t, nil, NewTrainer(n, WithSeed(seed), WithLambda(lambda)).Train(context.Background(), g))
+		}
+		return n
+	}
+	equal(t, train(1, 0, 20), train(1, 0, 20))
+	equal(t, train(1, 0.5, 20), train(1, 0.5, 20))
+	equal(t, false, equalWeights(train(1, 0, 20), train(2, 0, 20)))
+	equal(t, false, equalWeights(train(1, 0, 20), train(1, 0.5, 20)))
+
+	// training in two sessions learns the same weights as one session
+	equal(t, train(1, 0, 20), train(1, 0, 12, 8))
+}
+
+func TestTrainer_checkpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ntuple.weights")
+	n, _ := NewNetwork()
+	equal(t, nil, NewTrainer(n, WithCheckpoint(path, 4)).Train(context.Background(), 6))
+	saved, err := Load(path)
+	equal(t, nil, err)
+	equal(t, n, saved)
+
+	// a checkpoint is still written when training is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	equal(t, context.Canceled, NewTrainer(n, WithCheckpoint(path, 0)).Train(ctx, 10))
+	saved, err = Load(path)
+	equal(t, nil, err)
+	equal(t, int64(6), saved.Games())
+}
+
+func TestPlayer_NextMove(t *testing.T) {
+	n, _ := NewNetwork()
+	player := NewPlayer(n)
+
+	// an untrained network takes the most points
+	direction, ok := player.NextMove(game.Cells{
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 4, 4},
+		{2, 0, 0, 2},
+	})
+	equal(t, true, ok)
+	equal(t, true, direction == game.DirectionLeft || direction == game.DirectionRight)
+
+	_, ok = player.NextMove(game.Cells{{0, 2}, {2, 0}})
+	equal(t, false, ok)
+	_, ok = player.NextMove(game.Cells{
+		{2, 4, 2, 4},
+		{4, 2, 4, 2},
+		{2, 4, 2, 4},
+		{4, 2, 4, 2},
+	})
+	equal(t, false, ok)
+
+	// a trained network plays better than it did untrained
+	play := func() uint64 {
+		gc := game.NewController(game.WithSeed(1), game.WithBitboard())
+		for !gc.Lost() {
+			direction, _ := player.NextMove(gc.GetCells())
+			gc.Shift(direction)
+		}
+		return gc.GetScore()
+	}
+	untrained := play()
+	equal(t, nil, NewTrainer(n).Train(context.Background(), 300))
+	equal(t, true, play() > untrained)
+}
+
+func equalWeights(a, b *Network) bool {
+	for i := range a.weights {
+		for j := range a.weights[i] {
+			if a.weights[i][j] != b.weights[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
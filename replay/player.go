@@ -0,0 +1,104 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+// Player steps through a replay one action at a time
+type Player struct {
+	replay Replay
+	gc     game.Controller
+	pos    int
+}
+
+// NewPlayer builds a player positioned at the start of the replay
+func NewPlayer(r Replay) *Player {
+	return &Player{replay: r, gc: game.NewController(r.Options()...)}
+}
+
+// Replay returns the replay being played
+func (p *Player) Replay() Replay { return p.replay }
+
+// Controller returns the game as of the current position. A new controller is returned after stepping
+// back or seeking.
+func (p *Player) Controller() game.Controller { return p.gc }
+
+// Position returns the number of actions played so far
+func (p *Player) Position() int { return p.pos }
+
+// Len returns the number of actions in the replay
+func (p *Player) Len() int { return len(p.replay.Events) }
+
+// Done returns true once every action has been played
+func (p *Player) Done() bool { return p.pos == len(p.replay.Events) }
+
+// Step plays the next action. The ShiftResult describes the action if it shifted the board and is empty
+// for undo and redo. io.EOF is returned once every action has been played, and an error wrapping
+// ErrDiverged if the action did not apply to the game.
+func (p *Player) Step() (game.ShiftResult, error) {
+	if p.Done() {
+		return game.ShiftResult{}, io.EOF
+	}
+	event := p.replay.Events[p.pos]
+	var res game.ShiftResult
+	applied := false
+	switch event.Action {
+	case ActionUndo:
+		applied = p.gc.Undo()
+	case ActionRedo:
+		applied = p.gc.Redo()
+	default:
+		direction, _ := event.Action.Direction()
+		res = p.gc.Move(direction)
+		applied = res.Changed
+	}
+	if !applied {
+		return game.ShiftResult{}, fmt.Errorf("%w: action %d %q had no effect", ErrDiverged, p.pos+1, event.Action)
+	}
+	p.pos++
+	return res, nil
+}
+
+// Back returns to the position before the last action. False is returned at the start of the replay.
+func (p *Player) Back() bool {
+	if p.pos == 0 {
+		return false
+	}
+	// every action up to here has already applied successfully, so replaying them again cannot fail
+	_ = p.Seek(p.pos - 1)
+	return true
+}
+
+// Seek moves to the position after the given number of actions, replaying the game from its start
+func (p *Player) Seek(pos int) error {
+	if pos < 0 || pos > len(p.replay.Events) {
+		return fmt.Errorf("position %d is outside the replay of %d actions", pos, len(p.replay.Events))
+	}
+	if pos < p.pos {
+		p.gc = game.NewController(p.replay.Options()...)
+		p.pos = 0
+	}
+	for p.pos < pos {
+		if _, err := p.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NextDelay returns the time the player waited between the current position and the next action. False
+// is returned once every action has been played.
+func (p *Player) NextDelay() (time.Duration, bool) {
+	if p.Done() {
+		return 0, false
+	}
+	next := p.replay.Events[p.pos].At
+	if p.pos == 0 {
+		return next, true
+	}
+	return next - p.replay.Events[p.pos-1].At, true
+}
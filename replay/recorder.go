@@ -0,0 +1,149 @@
+package replay
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+// Recorder is a game.Controller that records every action made through it. Each game started by Reset is
+// recorded as a new replay.
+type Recorder struct {
+	game.Controller
+	replay    Replay
+	recording bool
+	start     time.Time
+	now       func() time.Time
+	onFinish  func(Replay)
+}
+
+var _ game.Controller = (*Recorder)(nil)
+
+// NewRecorder starts recording the game. The replay of each game is passed to onFinish when Reset starts a
+// new game, onFinish may be nil. A game that already has moves cannot be reproduced from its seed, so it
+// is not recorded and recording begins with the next game.
+func NewRecorder(gc game.Controller, onFinish func(Replay)) *Recorder {
+	r := &Recorder{Controller: gc, now: time.Now, onFinish: onFinish}
+	if gc.GetMoveCount() == 0 {
+		r.startReplay()
+	}
+	return r
+}
+
+// ResumeRecorder continues recording a game from its replay so far, for instance after the game was saved
+// and restored. An error wrapping ErrDiverged is returned if the replay does not reproduce the game.
+func ResumeRecorder(gc game.Controller, r Replay, onFinish func(Replay)) (*Recorder, error) {
+	replayed, err := r.Play()
+	if err != nil {
+		return nil, err
+	}
+	if replayed.Seed() != gc.Seed() || replayed.GetScore() != gc.GetScore() ||
+		replayed.GetMoveCount() != gc.GetMoveCount() || !equalCells(replayed.GetCells(), gc.GetCells()) {
+		return nil, fmt.Errorf("%w: the replay does not end on the current board", ErrDiverged)
+	}
+	rec := &Recorder{Controller: gc, replay: r, recording: true, now: time.Now, onFinish: onFinish}
+	// carry on the timeline from the last recorded action
+	rec.start = rec.now().Add(-r.Duration())
+	return rec, nil
+}
+
+// Recording returns true if the current game is being recorded
+func (r *Recorder) Recording() bool { return r.recording }
+
+// Replay returns the recording of the current game so far. False is returned if the game is not being
+// recorded.
+func (r *Recorder) Replay() (Replay, bool) {
+	if !r.recording {
+		return Replay{}, false
+	}
+	replay := r.replay
+	replay.Events = append([]Event(nil), r.replay.Events...)
+	return replay, true
+}
+
+func (r *Recorder) Shift(direction game.Direction) bool {
+	changed := r.Controller.Shift(direction)
+	if changed {
+		r.record(ShiftAction(direction))
+	}
+	return changed
+}
+
+func (r *Recorder) Move(direction game.Direction) game.ShiftResult {
+	res := r.Controller.Move(direction)
+	if res.Changed {
+		r.record(ShiftAction(direction))
+	}
+	return res
+}
+
+func (r *Recorder) Undo() bool {
+	ok := r.Controller.Undo()
+	if ok {
+		r.record(ActionUndo)
+	}
+	return ok
+}
+
+func (r *Recorder) Redo() bool {
+	ok := r.Controller.Redo()
+	if ok {
+		r.record(ActionRedo)
+	}
+	return ok
+}
+
+func (r *Recorder) Reset() {
+	if replay, ok := r.Replay(); ok && len(replay.Events) > 0 && r.onFinish != nil {
+		r.onFinish(replay)
+	}
+	r.Controller.Reset()
+	r.startReplay()
+}
+
+// UnmarshalBinary replaces the game, which stops recording until the next game as the restored game cannot
+// be reproduced from its seed
+func (r *Recorder) UnmarshalBinary(data []byte) error {
+	if err := r.Controller.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	r.recording = false
+	return nil
+}
+
+func (r *Recorder) startReplay() {
+	cells := r.Controller.GetCells()
+	r.start = r.now()
+	r.recording = true
+	r.replay = Replay{
+		Seed:      r.Controller.Seed(),
+		Rows:      cells.Rows(),
+		Cols:      cells.Cols(),
+		WinTarget: r.Controller.WinTarget(),
+		UndoLimit: r.Controller.UndosRemaining(),
+		Spawn:     r.Controller.SpawnPolicy(),
+		MaxCell:   r.Controller.MaxCell(),
+		Started:   r.start,
+	}
+}
+
+func (r *Recorder) record(action Action) {
+	if r.recording {
+		r.replay.Events = append(r.replay.Events, Event{Action: action, At: r.now().Sub(r.start)})
+	}
+}
+
+func equalCells(a, b game.Cells) bool {
+	if a.Rows() != b.Rows() || a.Cols() != b.Cols() {
+		return false
+	}
+	for rowIdx := range a {
+		for colIdx := range a[rowIdx] {
+			if a[rowIdx][colIdx] != b[rowIdx][colIdx] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,260 @@
+// Package replay records games as a compact sequence of moves and plays them back. The game places its
+// random tiles deterministically from its seed, so the seed, board settings and moves are all that is
+// needed to reproduce a game exactly.
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+// Version is the version of the replay format written by Replay.MarshalJSON. Version 1 replays, which do
+// not record a spawn policy or max cell, are still read and play back with game.EvenSpawns on the default
+// engine.
+const Version = 2
+
+// the largest cell values of the default and bitboard engines, which play the same game up to the point a
+// cell reaches the lower of the two
+var (
+	defaultMaxCell  = game.NewController(game.WithSeed(0)).MaxCell()
+	bitboardMaxCell = game.NewController(game.WithSeed(0), game.WithBitboard()).MaxCell()
+)
+
+var (
+	// ErrInvalidReplay is returned when replay data cannot be decoded or describes an invalid game
+	ErrInvalidReplay = errors.New("invalid replay")
+	// ErrDiverged is returned when a recorded action has no effect on the replayed game, which means the
+	// replay was not recorded with the same settings or game engine
+	ErrDiverged = errors.New("replay diverged from the recorded game")
+)
+
+// Action is a single recorded input, stored as one character in the replay format
+type Action byte
+
+const (
+	ActionLeft  Action = 'L'
+	ActionUp    Action = 'U'
+	ActionRight Action = 'R'
+	ActionDown  Action = 'D'
+	ActionUndo  Action = '-'
+	ActionRedo  Action = '+'
+)
+
+// ShiftAction returns the action that shifts the board in the given direction
+func ShiftAction(direction game.Direction) Action {
+	switch direction {
+	case game.DirectionLeft:
+		return ActionLeft
+	case game.DirectionUp:
+		return ActionUp
+	case game.DirectionRight:
+		return ActionRight
+	default:
+		return ActionDown
+	}
+}
+
+// Direction returns the direction shifted by the action. False is returned for undo and redo.
+func (a Action) Direction() (game.Direction, bool) {
+	switch a {
+	case ActionLeft:
+		return game.DirectionLeft, true
+	case ActionUp:
+		return game.DirectionUp, true
+	case ActionRight:
+		return game.DirectionRight, true
+	case ActionDown:
+		return game.DirectionDown, true
+	default:
+		return 0, false
+	}
+}
+
+func (a Action) valid() bool {
+	_, shift := a.Direction()
+	return shift || a == ActionUndo || a == ActionRedo
+}
+
+// Event is an action and the time it was made, measured from the start of the game
+type Event struct {
+	Action Action
+	At     time.Duration
+}
+
+// Replay is a recording of a single game
+type Replay struct {
+	// Seed the game was started with
+	Seed int64
+	// Rows and Cols of the game board
+	Rows, Cols int
+	// WinTarget is the cell value needed to win the game
+	WinTarget uint32
+	// UndoLimit is the number of undos allowed in the game, negative when undos are unlimited
+	UndoLimit int
+	// Spawn is the policy the game added random tiles with
+	Spawn game.SpawnPolicy
+	// MaxCell is the largest value a cell could hold, which tells apart games played on the bitboard engine.
+	// The default engine is used if it is 0.
+	MaxCell uint32
+	// Started is the time the game was started
+	Started time.Time
+	// Events lists every action that changed the game, in order
+	Events []Event
+}
+
+// Options returns the game options that build the starting board of the replay
+func (r Replay) Options() []game.Option {
+	options := []game.Option{
+		game.WithSize(r.Rows, r.Cols),
+		game.WithSeed(r.Seed),
+		game.WithWinTarget(r.WinTarget),
+		game.WithSpawnPolicy(r.Spawn),
+	}
+	if r.UndoLimit >= 0 {
+		options = append(options, game.WithUndoLimit(r.UndoLimit))
+	}
+	if r.MaxCell == bitboardMaxCell {
+		options = append(options, game.WithBitboard())
+	}
+	return options
+}
+
+// Duration returns the time from the start of the game to the last recorded action
+func (r Replay) Duration() time.Duration {
+	if len(r.Events) == 0 {
+		return 0
+	}
+	return r.Events[len(r.Events)-1].At
+}
+
+// Play replays every recorded action, returning the game as it was when the recording ended
+func (r Replay) Play() (game.Controller, error) {
+	p := NewPlayer(r)
+	if err := p.Seek(len(r.Events)); err != nil {
+		return nil, err
+	}
+	return p.Controller(), nil
+}
+
+type replayFile struct {
+	Version   int    `json:"version"`
+	Seed      int64  `json:"seed"`
+	Rows      int    `json:"rows"`
+	Cols      int    `json:"cols"`
+	WinTarget uint32 `json:"win_target"`
+	UndoLimit int    `json:"undo_limit"`
+	// Spawn is missing from version 1 replays
+	Spawn *game.SpawnPolicy `json:"spawn,omitempty"`
+	// MaxCell is missing from version 1 replays
+	MaxCell uint32    `json:"max_cell,omitempty"`
+	Started time.Time `json:"started"`
+	// Actions holds one character per action
+	Actions string `json:"actions"`
+	// Times holds the milliseconds from the start of the game to each action
+	Times []int64 `json:"times"`
+}
+
+// MarshalJSON encodes the replay in the versioned replay format
+func (r Replay) MarshalJSON() ([]byte, error) {
+	file := replayFile{
+		Version:   Version,
+		Seed:      r.Seed,
+		Rows:      r.Rows,
+		Cols:      r.Cols,
+		WinTarget: r.WinTarget,
+		UndoLimit: r.UndoLimit,
+		Spawn:     &r.Spawn,
+		MaxCell:   r.MaxCell,
+		Started:   r.Started,
+		Times:     make([]int64, len(r.Events)),
+	}
+	actions := make([]byte, len(r.Events))
+	for i, event := range r.Events {
+		actions[i] = byte(event.Action)
+		file.Times[i] = event.At.Milliseconds()
+	}
+	file.Actions = string(actions)
+	return json.Marshal(file)
+}
+
+// UnmarshalJSON decodes a replay encoded by MarshalJSON. An error wrapping ErrInvalidReplay is returned if
+// the data cannot be decoded, leaving the replay unchanged.
+func (r *Replay) UnmarshalJSON(data []byte) error {
+	var file replayFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReplay, err)
+	}
+	if err := file.validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReplay, err)
+	}
+	spawn, maxCell := game.EvenSpawns(), defaultMaxCell
+	if file.Version > 1 {
+		spawn, maxCell = *file.Spawn, file.MaxCell
+	}
+	events := make([]Event, len(file.Actions))
+	for i := range events {
+		events[i] = Event{Action: Action(file.Actions[i]), At: time.Duration(file.Times[i]) * time.Millisecond}
+	}
+	*r = Replay{
+		Seed:      file.Seed,
+		Rows:      file.Rows,
+		Cols:      file.Cols,
+		WinTarget: file.WinTarget,
+		UndoLimit: file.UndoLimit,
+		Spawn:     spawn,
+		MaxCell:   maxCell,
+		Started:   file.Started,
+		Events:    events,
+	}
+	return nil
+}
+
+func (f replayFile) validate() error {
+	if f.Version < 1 || f.Version > Version {
+		return fmt.Errorf("replay version %d is not supported, expected version %d", f.Version, Version)
+	}
+	if f.Version > 1 && f.Spawn == nil {
+		return errors.New("missing spawn policy")
+	}
+	if f.Spawn != nil {
+		if err := f.Spawn.Validate(); err != nil {
+			return fmt.Errorf("spawn policy: %w", err)
+		}
+	}
+	if f.Rows < 2 || f.Cols < 2 {
+		return fmt.Errorf("invalid board size %dx%d", f.Rows, f.Cols)
+	}
+	if f.WinTarget < 4 || f.WinTarget&(f.WinTarget-1) != 0 {
+		return fmt.Errorf("invalid win target %d", f.WinTarget)
+	}
+	if f.Version > 1 {
+		switch f.MaxCell {
+		case defaultMaxCell:
+		case bitboardMaxCell:
+			if f.Rows != 4 || f.Cols != 4 || f.Spawn.MaxValue() > bitboardMaxCell {
+				return fmt.Errorf("a game with max cell %d is only played on a 4x4 board spawning tiles up to %d",
+					f.MaxCell, bitboardMaxCell)
+			}
+		default:
+			return fmt.Errorf("max cell %d is not supported by any game engine", f.MaxCell)
+		}
+	}
+	if len(f.Times) != len(f.Actions) {
+		return fmt.Errorf("%d actions but %d times", len(f.Actions), len(f.Times))
+	}
+	var last int64
+	for i := 0; i < len(f.Actions); i++ {
+		if !Action(f.Actions[i]).valid() {
+			return fmt.Errorf("invalid action %q at index %d", f.Actions[i], i)
+		}
+		if f.Times[i] < last {
+			return fmt.Errorf("time of action %d is before the previous action", i)
+		}
+		last = f.Times[i]
+	}
+	return nil
+}
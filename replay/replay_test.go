@@ -0,0 +1,216 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+var directions = [...]game.Direction{game.DirectionLeft, game.DirectionUp, game.DirectionRight, game.DirectionDown}
+
+// recordGame plays a game of the given number of moves through a recorder, with an undo and redo along the way
+func recordGame(t *testing.T, moves int) (*Recorder, game.Controller) {
+	gc := game.NewController(game.WithSeed(7), game.WithSize(4, 5), game.WithUndoLimit(3),
+		game.WithSpawnPolicy(game.HardSpawns()))
+	rec := NewRecorder(gc, nil)
+	clock := time.Unix(0, 0)
+	rec.now = func() time.Time {
+		clock = clock.Add(250 * time.Millisecond)
+		return clock
+	}
+	rec.startReplay()
+	for i := 0; rec.GetMoveCount() < moves && !rec.Lost(); i++ {
+		rec.Shift(directions[i%len(directions)])
+		if i == 5 {
+			equal(t, true, rec.Undo())
+			equal(t, true, rec.Redo())
+			equal(t, true, rec.Undo())
+		}
+	}
+	return rec, gc
+}
+
+func TestRecorder(t *testing.T) {
+	rec, gc := recordGame(t, 30)
+	r, ok := rec.Replay()
+	equal(t, true, ok)
+	equal(t, int64(7), r.Seed)
+	equal(t, 4, r.Rows)
+	equal(t, 5, r.Cols)
+	equal(t, 3, r.UndoLimit)
+	equal(t, uint32(2048), r.WinTarget)
+	equal(t, game.HardSpawns(), r.Spawn)
+	equal(t, 34, len(r.Events))
+	equal(t, ActionUndo, r.Events[6].Action)
+	equal(t, ActionRedo, r.Events[7].Action)
+	equal(t, 250*time.Millisecond, r.Events[1].At-r.Events[0].At)
+
+	played, err := r.Play()
+	equal(t, nil, err)
+	equal(t, gc.GetCells(), played.GetCells())
+	equal(t, gc.GetScore(), played.GetScore())
+	equal(t, gc.UndosRemaining(), played.UndosRemaining())
+
+	// unchanged moves are not recorded
+	before := len(r.Events)
+	for _, direction := range directions {
+		if !rec.Shift(direction) {
+			break
+		}
+	}
+	r, _ = rec.Replay()
+	equal(t, true, len(r.Events) <= before+len(directions))
+	equal(t, rec.GetMoveCount(), len(r.Events)-4)
+}
+
+func TestRecorder_Reset(t *testing.T) {
+	var finished []Replay
+	gc := game.NewController(game.WithSeed(1))
+	rec := NewRecorder(gc, func(r Replay) { finished = append(finished, r) })
+	rec.Shift(game.DirectionLeft)
+	rec.Shift(game.DirectionUp)
+	rec.Reset()
+	equal(t, 1, len(finished))
+	equal(t, int64(1), finished[0].Seed)
+
+	r, ok := rec.Replay()
+	equal(t, true, ok)
+	equal(t, gc.Seed(), r.Seed)
+	equal(t, 0, len(r.Events))
+
+	// a game with no moves is not reported
+	rec.Reset()
+	equal(t, 1, len(finished))
+
+	// games that already have moves cannot be recorded
+	rec = NewRecorder(gc, nil)
+	rec.Shift(game.DirectionLeft)
+	rec.Shift(game.DirectionRight)
+	rec = NewRecorder(gc, nil)
+	_, ok = rec.Replay()
+	equal(t, false, ok)
+}
+
+func TestResumeRecorder(t *testing.T) {
+	rec, gc := recordGame(t, 20)
+	r, _ := rec.Replay()
+
+	resumed, err := ResumeRecorder(gc, r, nil)
+	equal(t, nil, err)
+	resumed.Shift(game.DirectionDown)
+	resumed.Shift(game.DirectionLeft)
+	r, _ = resumed.Replay()
+	played, err := r.Play()
+	equal(t, nil, err)
+	equal(t, gc.GetCells(), played.GetCells())
+
+	other := game.NewController(game.WithSeed(8))
+	_, err = ResumeRecorder(other, r, nil)
+	equal(t, true, errors.Is(err, ErrDiverged))
+}
+
+func TestReplay_JSON(t *testing.T) {
+	rec, _ := recordGame(t, 10)
+	r, _ := rec.Replay()
+	data, err := json.Marshal(r)
+	equal(t, nil, err)
+
+	var decoded Replay
+	equal(t, nil, json.Unmarshal(data, &decoded))
+	equal(t, r.Events, decoded.Events)
+	equal(t, r.Seed, decoded.Seed)
+	equal(t, r.Started.Equal(decoded.Started), true)
+	equal(t, r.Spawn, decoded.Spawn)
+	equal(t, r.MaxCell, decoded.MaxCell)
+
+	// replays recorded before spawn policies were configurable played with even spawns on the default engine
+	equal(t, nil, decoded.UnmarshalJSON([]byte(`{"version":1,"rows":4,"cols":4,"win_target":2048,"actions":"","times":[]}`)))
+	equal(t, game.EvenSpawns(), decoded.Spawn)
+	equal(t, defaultMaxCell, decoded.MaxCell)
+	equal(t, nil, json.Unmarshal(data, &decoded))
+
+	for name, data := range map[string]string{
+		"not json":      `{`,
+		"version":       `{"version":3,"rows":4,"cols":4,"win_target":2048,"actions":"","times":[]}`,
+		"no spawn":      `{"version":2,"rows":4,"cols":4,"win_target":2048,"actions":"","times":[]}`,
+		"bad spawn":     `{"version":2,"rows":4,"cols":4,"win_target":2048,"spawn":{"weights":[],"start":2,"per_move":1},"max_cell":2147483648,"actions":"","times":[]}`,
+		"no max cell":   `{"version":2,"rows":4,"cols":4,"win_target":2048,"spawn":{"weights":[{"value":2,"weight":1}],"start":2,"per_move":1},"actions":"","times":[]}`,
+		"max cell":      `{"version":2,"rows":4,"cols":4,"win_target":2048,"spawn":{"weights":[{"value":2,"weight":1}],"start":2,"per_move":1},"max_cell":4096,"actions":"","times":[]}`,
+		"bitboard size": `{"version":2,"rows":4,"cols":5,"win_target":2048,"spawn":{"weights":[{"value":2,"weight":1}],"start":2,"per_move":1},"max_cell":32768,"actions":"","times":[]}`,
+		"size":          `{"version":1,"rows":1,"cols":4,"win_target":2048,"actions":"","times":[]}`,
+		"target":        `{"version":1,"rows":4,"cols":4,"win_target":100,"actions":"","times":[]}`,
+		"action":        `{"version":1,"rows":4,"cols":4,"win_target":2048,"actions":"LX","times":[1,2]}`,
+		"times":         `{"version":1,"rows":4,"cols":4,"win_target":2048,"actions":"LU","times":[1]}`,
+		"times ordered": `{"version":1,"rows":4,"cols":4,"win_target":2048,"actions":"LU","times":[2,1]}`,
+	} {
+		err := decoded.UnmarshalJSON([]byte(data))
+		if !errors.Is(err, ErrInvalidReplay) {
+			t.Errorf("%s: expected ErrInvalidReplay, got %v", name, err)
+		}
+	}
+	equal(t, r.Events, decoded.Events)
+}
+
+func TestReplay_bitboard(t *testing.T) {
+	gc := game.NewController(game.WithSeed(4), game.WithBitboard())
+	rec := NewRecorder(gc, nil)
+	for i := 0; i < 20; i++ {
+		rec.Shift(directions[i%len(directions)])
+	}
+	r, _ := rec.Replay()
+	data, err := json.Marshal(r)
+	equal(t, nil, err)
+
+	// the replay plays back on the engine it was recorded on
+	var decoded Replay
+	equal(t, nil, json.Unmarshal(data, &decoded))
+	equal(t, gc.MaxCell(), decoded.MaxCell)
+	played, err := decoded.Play()
+	equal(t, nil, err)
+	equal(t, gc.MaxCell(), played.MaxCell())
+	equal(t, gc.GetCells(), played.GetCells())
+}
+
+func TestPlayer(t *testing.T) {
+	rec, gc := recordGame(t, 15)
+	r, _ := rec.Replay()
+	p := NewPlayer(r)
+	equal(t, 0, p.Position())
+	equal(t, false, p.Back())
+
+	var boards []game.Cells
+	for !p.Done() {
+		boards = append(boards, p.Controller().GetCells())
+		delay, ok := p.NextDelay()
+		equal(t, true, ok)
+		equal(t, true, delay >= 0)
+		_, err := p.Step()
+		equal(t, nil, err)
+	}
+	equal(t, gc.GetCells(), p.Controller().GetCells())
+	_, err := p.Step()
+	equal(t, io.EOF, err)
+
+	equal(t, true, p.Back())
+	equal(t, boards[len(boards)-1], p.Controller().GetCells())
+	equal(t, nil, p.Seek(3))
+	equal(t, boards[3], p.Controller().GetCells())
+	equal(t, true, p.Seek(p.Len()+1) != nil)
+
+	// an action that does nothing on the replayed board means the replay does not match the game
+	r.Events = append([]Event{{Action: ActionRedo}}, r.Events...)
+	_, err = NewPlayer(r).Step()
+	equal(t, true, errors.Is(err, ErrDiverged))
+}
+
+func equal(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,41 @@
+package server
+
+import "time"
+
+// Option configures a Server built by New
+type Option interface {
+	apply(s *Server)
+}
+
+// WithSessionTTL sets how long a game is kept after its last request, 30 minutes by default
+func WithSessionTTL(ttl time.Duration) Option {
+	return sessionTTLOption{ttl: ttl}
+}
+
+type sessionTTLOption struct {
+	ttl time.Duration
+}
+
+func (o sessionTTLOption) apply(s *Server) {
+	if o.ttl <= 0 {
+		panic("WithSessionTTL: ttl must be positive")
+	}
+	s.ttl = o.ttl
+}
+
+// WithMaxSessions limits the number of games in progress at once, 10000 by default. Requests to start a
+// game beyond the limit fail until older games expire or are deleted.
+func WithMaxSessions(max int) Option {
+	return maxSessionsOption{max: max}
+}
+
+type maxSessionsOption struct {
+	max int
+}
+
+func (o maxSessionsOption) apply(s *Server) {
+	if o.max < 1 {
+		panic("WithMaxSessions: max must be at least 1")
+	}
+	s.maxSessions = o.max
+}
@@ -0,0 +1,349 @@
+// Package server hosts games over HTTP with a JSON API. Each game is a session backed by its own
+// game.Controller, identified by a random ID and expired after a period without requests.
+//
+//	POST   /games              start a game, optionally with {"rows":4,"cols":4,"seed":1,"win_target":2048,"undo_limit":-1}
+//	GET    /games/{id}         fetch the board, score and status
+//	POST   /games/{id}/move    shift the board with {"direction":"left"}
+//	POST   /games/{id}/undo    undo the last move
+//	POST   /games/{id}/redo    redo the last undone move
+//	POST   /games/{id}/reset   start over with a new board
+//	DELETE /games/{id}         end the game
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/headless"
+)
+
+const (
+	defaultSessionTTL  = 30 * time.Minute
+	defaultMaxSessions = 10000
+	maxBoardSize       = 16
+	maxRequestBytes    = 1 << 12
+)
+
+// Server serves the game API. It is an http.Handler.
+type Server struct {
+	mu          sync.Mutex
+	sessions    map[string]*session
+	ttl         time.Duration
+	maxSessions int
+	now         func() time.Time
+	stop        chan struct{}
+	stopOnce    sync.Once
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// session is a single game, locked for the duration of each request
+type session struct {
+	mu       sync.Mutex
+	gc       game.Controller
+	lastUsed time.Time
+}
+
+// GameResponse is the body returned by every successful request for a game
+type GameResponse struct {
+	ID string `json:"id"`
+	headless.State
+}
+
+// ErrorResponse is the body returned by failed requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// CreateRequest is the optional body of a request to start a game. Unset fields use the game defaults.
+type CreateRequest struct {
+	Rows      int    `json:"rows"`
+	Cols      int    `json:"cols"`
+	Seed      *int64 `json:"seed"`
+	WinTarget uint32 `json:"win_target"`
+	UndoLimit *int   `json:"undo_limit"`
+}
+
+// MoveRequest is the body of a request to shift the board
+type MoveRequest struct {
+	Direction string `json:"direction"`
+}
+
+// New builds a server with no games. Games expire after 30 minutes without requests by default, and are
+// swept out in the background once per TTL until the server is stopped.
+func New(options ...Option) *Server {
+	s := &Server{
+		sessions:    make(map[string]*session),
+		ttl:         defaultSessionTTL,
+		maxSessions: defaultMaxSessions,
+		now:         time.Now,
+		stop:        make(chan struct{}),
+	}
+	for _, option := range options {
+		option.apply(s)
+	}
+	go s.sweepExpired(s.ttl)
+	return s
+}
+
+// Stop ends the background sweep of expired games. Requests are still served, expired games are then only
+// removed when they are requested or a game is started.
+func (s *Server) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// Sessions returns the number of games that have not expired
+func (s *Server) Sessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep()
+	return len(s.sessions)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "games" || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch len(parts) {
+	case 1:
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.create(w, r)
+	case 2:
+		switch r.Method {
+		case http.MethodGet:
+			s.withSession(w, parts[1], func(gc game.Controller) headless.State {
+				return headless.NewState(gc)
+			})
+		case http.MethodDelete:
+			s.delete(w, parts[1])
+		default:
+			writeMethodNotAllowed(w, http.MethodGet, http.MethodDelete)
+		}
+	case 3:
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.action(w, r, parts[1], parts[2])
+	}
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var req CreateRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	options, err := req.options()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := newID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	gc := game.NewController(options...)
+
+	s.mu.Lock()
+	s.sweep()
+	if len(s.sessions) >= s.maxSessions {
+		s.mu.Unlock()
+		writeError(w, http.StatusServiceUnavailable, "too many games in progress, try again later")
+		return
+	}
+	s.sessions[id] = &session{gc: gc, lastUsed: s.now()}
+	s.mu.Unlock()
+
+	w.Header().Set("Location", "/games/"+id)
+	writeJSON(w, http.StatusCreated, GameResponse{ID: id, State: headless.NewState(gc)})
+}
+
+func (s *Server) delete(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	_, ok := s.lookup(id)
+	delete(s.sessions, id)
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "game not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) action(w http.ResponseWriter, r *http.Request, id, action string) {
+	switch action {
+	case "move":
+		var req MoveRequest
+		if err := decodeBody(r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		direction, ok := headless.ParseDirection(req.Direction)
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown direction %q", req.Direction))
+			return
+		}
+		s.withSession(w, id, func(gc game.Controller) headless.State {
+			res := gc.Move(direction)
+			state := headless.NewState(gc)
+			state.Changed = res.Changed
+			state.ScoreDelta = res.ScoreDelta
+			return state
+		})
+	case "undo":
+		s.withSession(w, id, func(gc game.Controller) headless.State {
+			changed := gc.Undo()
+			state := headless.NewState(gc)
+			state.Changed = changed
+			return state
+		})
+	case "redo":
+		s.withSession(w, id, func(gc game.Controller) headless.State {
+			changed := gc.Redo()
+			state := headless.NewState(gc)
+			state.Changed = changed
+			return state
+		})
+	case "reset":
+		s.withSession(w, id, func(gc game.Controller) headless.State {
+			gc.Reset()
+			state := headless.NewState(gc)
+			state.Changed = true
+			return state
+		})
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// withSession runs fn on the game while holding its lock and writes the resulting state
+func (s *Server) withSession(w http.ResponseWriter, id string, fn func(gc game.Controller) headless.State) {
+	s.mu.Lock()
+	sess, ok := s.lookup(id)
+	if ok {
+		sess.lastUsed = s.now()
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "game not found")
+		return
+	}
+
+	sess.mu.Lock()
+	state := fn(sess.gc)
+	sess.mu.Unlock()
+	writeJSON(w, http.StatusOK, GameResponse{ID: id, State: state})
+}
+
+// lookup returns the session unless it has expired, the server lock must be held
+func (s *Server) lookup(id string) (*session, bool) {
+	sess, ok := s.sessions[id]
+	if ok && s.expired(sess) {
+		delete(s.sessions, id)
+		return nil, false
+	}
+	return sess, ok
+}
+
+// sweep removes every expired session, the server lock must be held
+func (s *Server) sweep() {
+	for id, sess := range s.sessions {
+		if s.expired(sess) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// sweepExpired removes expired sessions every interval until the server is stopped, so games nobody
+// requests again do not wait for the next game to be started
+func (s *Server) sweepExpired(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			s.sweep()
+			s.mu.Unlock()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Server) expired(sess *session) bool {
+	return s.now().Sub(sess.lastUsed) > s.ttl
+}
+
+func (r CreateRequest) options() ([]game.Option, error) {
+	var options []game.Option
+	if r.Rows != 0 || r.Cols != 0 {
+		if r.Rows < 2 || r.Cols < 2 || r.Rows > maxBoardSize || r.Cols > maxBoardSize {
+			return nil, fmt.Errorf("rows and cols must be between 2 and %d", maxBoardSize)
+		}
+		options = append(options, game.WithSize(r.Rows, r.Cols))
+	}
+	if r.Seed != nil {
+		options = append(options, game.WithSeed(*r.Seed))
+	}
+	if r.WinTarget != 0 {
+		if r.WinTarget < 4 || r.WinTarget&(r.WinTarget-1) != 0 {
+			return nil, errors.New("win_target must be a power of two of at least 4")
+		}
+		options = append(options, game.WithWinTarget(r.WinTarget))
+	}
+	if r.UndoLimit != nil && *r.UndoLimit >= 0 {
+		options = append(options, game.WithUndoLimit(*r.UndoLimit))
+	}
+	return options, nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating game id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// decodeBody decodes a JSON request body into v, an empty body leaves v unchanged
+func decodeBody(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil && err != io.EOF {
+		return fmt.Errorf("invalid request body: %v", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// the status is already written, so there is nothing more to do if the client has gone away
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, ErrorResponse{Error: msg})
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+}
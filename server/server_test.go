@@ -0,0 +1,196 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+func do(t *testing.T, srv *httptest.Server, method, path, body string, v interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestServer_game(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	reference := game.NewController(game.WithSeed(5), game.WithSize(3, 4), game.WithUndoLimit(1))
+
+	var created GameResponse
+	status := do(t, srv, http.MethodPost, "/games", `{"rows":3,"cols":4,"seed":5,"undo_limit":1}`, &created)
+	equal(t, http.StatusCreated, status)
+	equal(t, 32, len(created.ID))
+	equal(t, reference.GetCells(), created.Cells)
+
+	var moved GameResponse
+	equal(t, http.StatusOK, do(t, srv, http.MethodPost, "/games/"+created.ID+"/move", `{"direction":"left"}`, &moved))
+	res := reference.Move(game.DirectionLeft)
+	equal(t, reference.GetCells(), moved.Cells)
+	equal(t, res.Changed, moved.Changed)
+	equal(t, reference.GetScore(), moved.Score)
+
+	var fetched GameResponse
+	equal(t, http.StatusOK, do(t, srv, http.MethodGet, "/games/"+created.ID, "", &fetched))
+	equal(t, moved.State.Cells, fetched.Cells)
+	equal(t, false, fetched.Changed)
+
+	var undone GameResponse
+	equal(t, http.StatusOK, do(t, srv, http.MethodPost, "/games/"+created.ID+"/undo", "", &undone))
+	equal(t, created.Cells, undone.Cells)
+	equal(t, true, undone.Changed)
+	// the undo limit of 1 is spent
+	do(t, srv, http.MethodPost, "/games/"+created.ID+"/redo", "", &undone)
+	equal(t, http.StatusOK, do(t, srv, http.MethodPost, "/games/"+created.ID+"/undo", "", &undone))
+	equal(t, false, undone.Changed)
+
+	var reset GameResponse
+	equal(t, http.StatusOK, do(t, srv, http.MethodPost, "/games/"+created.ID+"/reset", "", &reset))
+	equal(t, 0, reset.Moves)
+
+	equal(t, http.StatusNoContent, do(t, srv, http.MethodDelete, "/games/"+created.ID, "", nil))
+	equal(t, http.StatusNotFound, do(t, srv, http.MethodGet, "/games/"+created.ID, "", &ErrorResponse{}))
+}
+
+func TestServer_errors(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+
+	var created GameResponse
+	equal(t, http.StatusCreated, do(t, srv, http.MethodPost, "/games", "", &created))
+	equal(t, 4, created.Cells.Rows())
+
+	for _, tc := range []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodPost, "/games", `{"rows":1,"cols":4}`, http.StatusBadRequest},
+		{http.MethodPost, "/games", `{"rows":100,"cols":100}`, http.StatusBadRequest},
+		{http.MethodPost, "/games", `{"win_target":100}`, http.StatusBadRequest},
+		{http.MethodPost, "/games", `{"colour":"red"}`, http.StatusBadRequest},
+		{http.MethodPost, "/games", `{`, http.StatusBadRequest},
+		{http.MethodGet, "/games", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/players", "", http.StatusNotFound},
+		{http.MethodGet, "/games/unknown", "", http.StatusNotFound},
+		{http.MethodPut, "/games/" + created.ID, "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/games/" + created.ID + "/move", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/games/" + created.ID + "/move", `{"direction":"sideways"}`, http.StatusBadRequest},
+		{http.MethodPost, "/games/" + created.ID + "/jump", "", http.StatusNotFound},
+		{http.MethodPost, "/games/unknown/move", `{"direction":"up"}`, http.StatusNotFound},
+	} {
+		var resp ErrorResponse
+		status := do(t, srv, tc.method, tc.path, tc.body, &resp)
+		if status != tc.status || resp.Error == "" {
+			t.Errorf("%s %s %s: expected status %d with an error, got %d %q",
+				tc.method, tc.path, tc.body, tc.status, status, resp.Error)
+		}
+	}
+}
+
+func TestServer_expiry(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := New(WithSessionTTL(time.Minute), WithMaxSessions(2))
+	s.now = func() time.Time { return now }
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	var first, second GameResponse
+	do(t, srv, http.MethodPost, "/games", "", &first)
+	now = now.Add(40 * time.Second)
+	do(t, srv, http.MethodPost, "/games", "", &second)
+	equal(t, http.StatusServiceUnavailable, do(t, srv, http.MethodPost, "/games", "", &ErrorResponse{}))
+
+	// using a game keeps it alive
+	now = now.Add(40 * time.Second)
+	equal(t, http.StatusOK, do(t, srv, http.MethodGet, "/games/"+second.ID, "", &GameResponse{}))
+	equal(t, http.StatusNotFound, do(t, srv, http.MethodGet, "/games/"+first.ID, "", &ErrorResponse{}))
+	equal(t, 1, s.Sessions())
+	equal(t, http.StatusCreated, do(t, srv, http.MethodPost, "/games", "", &GameResponse{}))
+
+	now = now.Add(2 * time.Minute)
+	equal(t, 0, s.Sessions())
+}
+
+func TestServer_sweep(t *testing.T) {
+	s := New(WithSessionTTL(10 * time.Millisecond))
+	defer s.Stop()
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	do(t, srv, http.MethodPost, "/games", "", &GameResponse{})
+	// nothing is requested again, the background sweep removes the game on its own
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mu.Lock()
+		sessions := len(s.sessions)
+		s.mu.Unlock()
+		if sessions == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expired game was not swept")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	// stopping again, as the deferred call does, is harmless
+	s.Stop()
+}
+
+func TestServer_concurrentMoves(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	var created GameResponse
+	do(t, srv, http.MethodPost, "/games", `{"seed":9}`, &created)
+
+	// moves on the same game are serialised, so every changed move is counted exactly once
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		changed int
+	)
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			direction := [...]string{"left", "up", "right", "down"}[i%4]
+			var resp GameResponse
+			do(t, srv, http.MethodPost, "/games/"+created.ID+"/move", `{"direction":"`+direction+`"}`, &resp)
+			if resp.Changed {
+				mu.Lock()
+				changed++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	var fetched GameResponse
+	do(t, srv, http.MethodGet, "/games/"+created.ID, "", &fetched)
+	equal(t, changed, fetched.Moves)
+}
+
+func equal(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,52 @@
+package solver
+
+import "github.com/brandenc40/2048/game"
+
+// Adversary places each new tile where it hurts the player most. Every empty cell and spawn value is tried,
+// keeping the tile that leaves the player's best reply with the lowest value found by an expectimax search.
+type Adversary struct {
+	expectimax *Expectimax
+}
+
+var _ game.Adversary = (*Adversary)(nil)
+
+// NewAdversary builds an adversary that judges the player's replies with an expectimax search configured by
+// the options. By default the search looks 2 moves ahead using DefaultHeuristic.
+func NewAdversary(options ...ExpectimaxOption) *Adversary {
+	return &Adversary{expectimax: NewExpectimax(options...)}
+}
+
+// Place returns the tile that minimises the value of the player's best reply. A tile that leaves the player
+// without a move is always chosen, ties go to the first cell in row then column order and the smallest value.
+func (a *Adversary) Place(cells game.Cells, spawn game.SpawnPolicy) game.Tile {
+	var (
+		best      game.Tile
+		bestValue float64
+		found     bool
+	)
+	for _, pos := range cells.EmptyPositions() {
+		for _, value := range spawnValues(spawn) {
+			child := cells.Clone()
+			child[pos.Row][pos.Col] = value
+			replyValue := lostValue
+			if values := a.expectimax.Evaluate(child); len(values) > 0 {
+				replyValue = values[0].Value
+			}
+			if !found || replyValue < bestValue {
+				best, bestValue, found = game.Tile{Position: pos, Value: value}, replyValue, true
+			}
+		}
+	}
+	return best
+}
+
+// spawnValues returns the distinct values the policy spawns in increasing order
+func spawnValues(policy game.SpawnPolicy) []uint32 {
+	var values []uint32
+	for value := uint32(2); value != 0 && value <= policy.MaxValue(); value <<= 1 {
+		if policy.Probability(value) > 0 {
+			values = append(values, value)
+		}
+	}
+	return values
+}
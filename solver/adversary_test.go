@@ -0,0 +1,42 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/brandenc40/2048/game"
+)
+
+func TestAdversary_Place(t *testing.T) {
+	a := NewAdversary(WithDepth(1))
+
+	// a 2 in the last cell leaves no moves, a 4 could still merge
+	tile := a.Place(game.Cells{
+		{2, 4},
+		{4, 0},
+	}, game.ClassicSpawns())
+	equal(t, game.Tile{Position: game.Position{Row: 1, Col: 1}, Value: 2}, tile)
+
+	// only an 8 cannot merge with its neighbours
+	tile = a.Place(game.Cells{
+		{16, 2},
+		{4, 0},
+	}, game.EasySpawns())
+	equal(t, game.Tile{Position: game.Position{Row: 1, Col: 1}, Value: 8}, tile)
+}
+
+func TestAdversary_playsGame(t *testing.T) {
+	play := func(options ...game.Option) game.Controller {
+		gc := game.NewController(append(options, game.WithSeed(1))...)
+		e := NewExpectimax()
+		for i := 0; i < 300 && !gc.Lost(); i++ {
+			direction, _ := e.NextMove(gc.GetCells())
+			gc.Shift(direction)
+		}
+		return gc
+	}
+	// the same player loses far sooner when the tiles are placed against it
+	random := play()
+	adversarial := play(game.WithAdversary(NewAdversary(WithDepth(1))))
+	equal(t, true, adversarial.Lost())
+	equal(t, true, adversarial.GetMoveCount() < random.GetMoveCount())
+}
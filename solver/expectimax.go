@@ -0,0 +1,275 @@
+package solver
+
+import (
+	"context"
+	"sort"
+
+	"github.com/brandenc40/2048/game"
+)
+
+const (
+	defaultExpectimaxDepth = 2
+	// defaultMinProbability stops searching branches that are too unlikely to affect the result
+	defaultMinProbability = 0.0001
+	// lostValue is the value of a board with no moves remaining
+	lostValue = -1e9
+	// cancelCheckInterval is the number of nodes searched between checks of the search context
+	cancelCheckInterval = 1024
+	// defaultMaxCell is the largest cell of the game's default engine, the solvers plan for it unless told
+	// the game's Controller.MaxCell
+	defaultMaxCell = 1 << 31
+)
+
+// spawn is a cell value the game can add after a move and the probability of it being chosen
+type spawn struct {
+	value       uint32
+	probability float64
+}
+
+// defaultSpawns matches the game's default game.ClassicSpawns policy
+var defaultSpawns = spawnsOf(game.ClassicSpawns())
+
+// spawnsOf returns the values the policy spawns with their probabilities
+func spawnsOf(policy game.SpawnPolicy) []spawn {
+	var spawns []spawn
+	for _, w := range policy.Weights {
+		if !containsSpawn(spawns, w.Value) {
+			spawns = append(spawns, spawn{value: w.Value, probability: policy.Probability(w.Value)})
+		}
+	}
+	return spawns
+}
+
+func containsSpawn(spawns []spawn, value uint32) bool {
+	for _, s := range spawns {
+		if s.value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Expectimax chooses moves by searching every move and every possible random cell placement to a fixed
+// depth, picking the move with the best expected heuristic value
+type Expectimax struct {
+	depth          int
+	heuristic      Heuristic
+	minProbability float64
+	spawns         []spawn
+	maxCell        uint32
+}
+
+var _ Strategy = (*Expectimax)(nil)
+
+// MoveValue is the result of searching a single move
+type MoveValue struct {
+	Direction game.Direction
+	// Value is the expected heuristic value of the board after the move
+	Value float64
+	// Score is the number of points the move earns immediately
+	Score uint64
+}
+
+// NewExpectimax builds an expectimax search. By default it searches 2 moves ahead using DefaultHeuristic.
+func NewExpectimax(options ...ExpectimaxOption) *Expectimax {
+	e := &Expectimax{
+		depth:          defaultExpectimaxDepth,
+		heuristic:      DefaultHeuristic(),
+		minProbability: defaultMinProbability,
+		spawns:         defaultSpawns,
+		maxCell:        defaultMaxCell,
+	}
+	for _, option := range options {
+		option.apply(e)
+	}
+	return e
+}
+
+// NextMove returns the move with the highest expected value
+func (e *Expectimax) NextMove(cells game.Cells) (game.Direction, bool) {
+	values := e.Evaluate(cells)
+	if len(values) == 0 {
+		return 0, false
+	}
+	return values[0].Direction, true
+}
+
+// Evaluate searches every move that changes the board, returning their values ordered best first
+func (e *Expectimax) Evaluate(cells game.Cells) []MoveValue {
+	values, _ := e.evaluate(context.Background(), cells, e.depth)
+	return values
+}
+
+// Search evaluates every move like Evaluate, deepening the search one level at a time up to the configured
+// depth until ctx is done. The values of the deepest completed level are returned along with that depth.
+// The first level only scores the board after each move and always completes, so a result is available
+// however short the deadline.
+func (e *Expectimax) Search(ctx context.Context, cells game.Cells) (values []MoveValue, depth int) {
+	values, _ = e.evaluate(context.Background(), cells, 1)
+	depth = 1
+	for d := 2; d <= e.depth; d++ {
+		deeper, ok := e.evaluate(ctx, cells, d)
+		if !ok {
+			break
+		}
+		values, depth = deeper, d
+	}
+	return values, depth
+}
+
+// evaluate searches every move to the given depth. False is returned if ctx was done before the search completed.
+func (e *Expectimax) evaluate(ctx context.Context, cells game.Cells, depth int) ([]MoveValue, bool) {
+	if ctx.Err() != nil {
+		return nil, false
+	}
+	s := search{Expectimax: e, ctx: ctx}
+	var values []MoveValue
+	for _, direction := range Directions {
+		shifted, score, changed := cells.ShiftCapped(direction, e.maxCell)
+		if !changed {
+			continue
+		}
+		values = append(values, MoveValue{
+			Direction: direction,
+			Value:     s.chanceNode(shifted, depth, 1),
+			Score:     score,
+		})
+		if s.aborted {
+			return nil, false
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Value > values[j].Value })
+	return values, true
+}
+
+// search holds the state of a single search, which is abandoned once its context is done
+type search struct {
+	*Expectimax
+	ctx     context.Context
+	nodes   int
+	aborted bool
+}
+
+// cancelled checks the context every cancelCheckInterval nodes, returning true once the search is abandoned
+func (s *search) cancelled() bool {
+	s.nodes++
+	if !s.aborted && s.nodes%cancelCheckInterval == 0 && s.ctx.Err() != nil {
+		s.aborted = true
+	}
+	return s.aborted
+}
+
+// maxNode returns the value of the best move from the board
+func (s *search) maxNode(cells game.Cells, depth int, probability float64) float64 {
+	if s.cancelled() {
+		return 0
+	}
+	best, moved := lostValue, false
+	for _, direction := range Directions {
+		shifted, _, changed := cells.ShiftCapped(direction, s.maxCell)
+		if !changed {
+			continue
+		}
+		moved = true
+		if value := s.chanceNode(shifted, depth, probability); value > best {
+			best = value
+		}
+	}
+	if !moved {
+		return lostValue
+	}
+	return best
+}
+
+// chanceNode returns the expected value of the board over every random cell the game could add to it
+func (s *search) chanceNode(cells game.Cells, depth int, probability float64) float64 {
+	if depth <= 1 || probability < s.minProbability {
+		return s.heuristic(cells)
+	}
+	empty := cells.EmptyPositions()
+	if len(empty) == 0 {
+		return s.heuristic(cells)
+	}
+	var expected float64
+	for _, pos := range empty {
+		for _, spawn := range s.spawns {
+			p := spawn.probability / float64(len(empty))
+			child := cells.Clone()
+			child[pos.Row][pos.Col] = spawn.value
+			expected += p * s.maxNode(child, depth-1, probability*p)
+		}
+	}
+	return expected
+}
+
+// ExpectimaxOption configures an Expectimax search
+type ExpectimaxOption interface {
+	apply(e *Expectimax)
+}
+
+// WithDepth sets the number of moves searched ahead, including the move being chosen. Each extra level
+// multiplies the search time by roughly the number of empty cells times eight.
+func WithDepth(depth int) ExpectimaxOption {
+	return depthOption{depth: depth}
+}
+
+type depthOption struct {
+	depth int
+}
+
+func (o depthOption) apply(e *Expectimax) {
+	if o.depth < 1 {
+		panic("WithDepth: depth must be at least 1")
+	}
+	e.depth = o.depth
+}
+
+// WithHeuristic sets the heuristic used to score boards at the end of the search
+func WithHeuristic(heuristic Heuristic) ExpectimaxOption {
+	return heuristicOption{heuristic: heuristic}
+}
+
+type heuristicOption struct {
+	heuristic Heuristic
+}
+
+func (o heuristicOption) apply(e *Expectimax) {
+	if o.heuristic == nil {
+		panic("WithHeuristic: heuristic must not be nil")
+	}
+	e.heuristic = o.heuristic
+}
+
+// WithSpawnPolicy sets the odds of the random cells searched after each move to those of the game's policy.
+// Only the first of the cells added after a move is searched when the policy adds more than one.
+func WithSpawnPolicy(policy game.SpawnPolicy) ExpectimaxOption {
+	return spawnPolicyOption{policy: policy}
+}
+
+type spawnPolicyOption struct {
+	policy game.SpawnPolicy
+}
+
+func (o spawnPolicyOption) apply(e *Expectimax) {
+	if err := o.policy.Validate(); err != nil {
+		panic("WithSpawnPolicy: " + err.Error())
+	}
+	e.spawns = spawnsOf(o.policy)
+}
+
+// WithMaxCell sets the largest value a cell can hold to the game's Controller.MaxCell, so merges the game
+// refuses are not searched. By default cells merge up to 2^31.
+func WithMaxCell(maxCell uint32) ExpectimaxOption {
+	return maxCellOption{maxCell: maxCell}
+}
+
+type maxCellOption struct {
+	maxCell uint32
+}
+
+func (o maxCellOption) apply(e *Expectimax) {
+	if o.maxCell < 4 || o.maxCell&(o.maxCell-1) != 0 {
+		panic("WithMaxCell: max cell must be a power of two of at least 4")
+	}
+	e.maxCell = o.maxCell
+}
@@ -0,0 +1,117 @@
+package solver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+func TestExpectimax_NextMove(t *testing.T) {
+	e := NewExpectimax(WithDepth(2))
+
+	// merging the two 512 tiles into the corner is clearly the best move
+	direction, ok := e.NextMove(game.Cells{
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{2, 0, 0, 0},
+		{512, 512, 4, 2},
+	})
+	equal(t, true, ok)
+	equal(t, game.DirectionLeft, direction)
+
+	// a board with no moves remaining
+	_, ok = e.NextMove(game.Cells{
+		{2, 4, 2, 4},
+		{4, 2, 4, 2},
+		{2, 4, 2, 4},
+		{4, 2, 4, 2},
+	})
+	equal(t, false, ok)
+}
+
+func TestExpectimax_Evaluate(t *testing.T) {
+	e := NewExpectimax(WithDepth(1), WithHeuristic(EmptyCells))
+	values := e.Evaluate(game.Cells{
+		{2, 2, 4, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	// only the moves that change the board are returned, best first
+	equal(t, 3, len(values))
+	equal(t, 14.0, values[0].Value)
+	equal(t, uint64(4), values[0].Score)
+	for i := 1; i < len(values); i++ {
+		equal(t, true, values[i-1].Value >= values[i].Value)
+	}
+
+	values = e.Evaluate(game.Cells{
+		{2, 4},
+		{4, 8},
+	})
+	equal(t, 0, len(values))
+}
+
+func TestExpectimax_Search(t *testing.T) {
+	e := NewExpectimax(WithDepth(3))
+	cells := game.Cells{
+		{0, 0, 0, 0},
+		{0, 2, 0, 0},
+		{2, 0, 0, 0},
+		{512, 512, 4, 2},
+	}
+
+	// without a deadline the search reaches the configured depth and matches Evaluate
+	values, depth := e.Search(context.Background(), cells)
+	equal(t, 3, depth)
+	equal(t, e.Evaluate(cells), values)
+
+	// a context that is already done still returns the first level
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	values, depth = e.Search(ctx, cells)
+	equal(t, 1, depth)
+	equal(t, 4, len(values))
+
+	// a deep search is cut short by its deadline
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, depth = NewExpectimax(WithDepth(8)).Search(ctx, cells)
+	equal(t, true, depth < 8)
+	equal(t, true, time.Since(start) < time.Second)
+}
+
+func TestExpectimax_playsGame(t *testing.T) {
+	gc := game.NewController(game.WithSeed(1))
+	e := NewExpectimax()
+	for i := 0; i < 200 && !gc.Lost(); i++ {
+		direction, ok := e.NextMove(gc.GetCells())
+		equal(t, true, ok)
+		equal(t, true, gc.Shift(direction))
+	}
+	equal(t, true, gc.GetCells().MaxValue() >= 128)
+}
+
+func TestWithSpawnPolicy(t *testing.T) {
+	equal(t, []spawn{{value: 2, probability: 0.9}, {value: 4, probability: 0.1}}, NewExpectimax().spawns)
+	e := NewExpectimax(WithSpawnPolicy(game.EasySpawns()))
+	equal(t, []spawn{{value: 2, probability: 0.6}, {value: 4, probability: 0.3}, {value: 8, probability: 0.1}}, e.spawns)
+}
+
+func TestWithMaxCell(t *testing.T) {
+	cells := game.Cells{
+		{1 << 15, 1 << 15, 0, 0},
+		{2, 4, 0, 0},
+		{4, 2, 0, 0},
+		{2, 4, 0, 0},
+	}
+	// merging the two 32768 tiles earns the most points, unless the game caps cells at 32768
+	values := NewExpectimax(WithDepth(1)).Evaluate(cells)
+	equal(t, uint64(1<<16), values[0].Score)
+	for _, v := range NewExpectimax(WithDepth(1), WithMaxCell(1<<15)).Evaluate(cells) {
+		equal(t, uint64(0), v.Score)
+	}
+}
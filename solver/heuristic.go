@@ -0,0 +1,145 @@
+package solver
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/brandenc40/2048/game"
+)
+
+// Heuristic scores how favourable a board is, higher values are better
+type Heuristic func(cells game.Cells) float64
+
+// WeightedHeuristic pairs a Heuristic with the weight it contributes to a combined score
+type WeightedHeuristic struct {
+	Heuristic Heuristic
+	Weight    float64
+}
+
+// Combine builds a Heuristic that sums the weighted scores of each heuristic
+func Combine(heuristics ...WeightedHeuristic) Heuristic {
+	return func(cells game.Cells) float64 {
+		var score float64
+		for _, h := range heuristics {
+			score += h.Weight * h.Heuristic(cells)
+		}
+		return score
+	}
+}
+
+// DefaultHeuristic balances keeping the board ordered, smooth and open with the largest tiles in a corner
+func DefaultHeuristic() Heuristic {
+	return Combine(
+		WeightedHeuristic{Heuristic: Monotonicity, Weight: 1},
+		WeightedHeuristic{Heuristic: Smoothness, Weight: 0.1},
+		WeightedHeuristic{Heuristic: EmptyCells, Weight: 2.7},
+		WeightedHeuristic{Heuristic: CornerWeight, Weight: 1},
+	)
+}
+
+// Monotonicity penalises rows and columns whose tiles do not steadily increase or decrease. The score is
+// zero for a perfectly ordered board and negative otherwise.
+func Monotonicity(cells game.Cells) float64 {
+	var score float64
+	for rowIdx := range cells {
+		var inc, dec float64
+		for colIdx := 1; colIdx < len(cells[rowIdx]); colIdx++ {
+			diff := exponent(cells[rowIdx][colIdx]) - exponent(cells[rowIdx][colIdx-1])
+			if diff > 0 {
+				inc += diff
+			} else {
+				dec -= diff
+			}
+		}
+		score -= math.Min(inc, dec)
+	}
+	for colIdx := 0; colIdx < cells.Cols(); colIdx++ {
+		var inc, dec float64
+		for rowIdx := 1; rowIdx < len(cells); rowIdx++ {
+			diff := exponent(cells[rowIdx][colIdx]) - exponent(cells[rowIdx-1][colIdx])
+			if diff > 0 {
+				inc += diff
+			} else {
+				dec -= diff
+			}
+		}
+		score -= math.Min(inc, dec)
+	}
+	return score
+}
+
+// Smoothness penalises neighbouring tiles with very different values, which are hard to merge. The score
+// is zero when every neighbouring pair of tiles is equal and negative otherwise.
+func Smoothness(cells game.Cells) float64 {
+	var score float64
+	for rowIdx, row := range cells {
+		for colIdx, value := range row {
+			if value == 0 {
+				continue
+			}
+			if colIdx+1 < len(row) && row[colIdx+1] != 0 {
+				score -= math.Abs(exponent(value) - exponent(row[colIdx+1]))
+			}
+			if rowIdx+1 < len(cells) && cells[rowIdx+1][colIdx] != 0 {
+				score -= math.Abs(exponent(value) - exponent(cells[rowIdx+1][colIdx]))
+			}
+		}
+	}
+	return score
+}
+
+// EmptyCells scores the number of empty cells, open boards leave more room to manoeuvre
+func EmptyCells(cells game.Cells) float64 {
+	var empty float64
+	for _, row := range cells {
+		for _, value := range row {
+			if value == 0 {
+				empty++
+			}
+		}
+	}
+	return empty
+}
+
+// CornerWeight rewards boards with their largest tiles gathered in a corner. Each tile contributes its
+// exponent weighted by its closeness to the best corner.
+func CornerWeight(cells game.Cells) float64 {
+	var (
+		rows    = cells.Rows()
+		cols    = cells.Cols()
+		maxDist = float64(rows + cols - 2)
+		best    = math.Inf(-1)
+	)
+	corners := [...]game.Position{
+		{Row: 0, Col: 0},
+		{Row: 0, Col: cols - 1},
+		{Row: rows - 1, Col: 0},
+		{Row: rows - 1, Col: cols - 1},
+	}
+	for _, corner := range corners {
+		var score float64
+		for rowIdx, row := range cells {
+			for colIdx, value := range row {
+				dist := float64(abs(rowIdx-corner.Row) + abs(colIdx-corner.Col))
+				score += exponent(value) * (maxDist - dist) / maxDist
+			}
+		}
+		best = math.Max(best, score)
+	}
+	return best
+}
+
+// exponent returns the power of two of a cell value, zero for empty cells
+func exponent(value uint32) float64 {
+	if value == 0 {
+		return 0
+	}
+	return float64(bits.TrailingZeros32(value))
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,85 @@
+package solver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/brandenc40/2048/game"
+)
+
+func TestMonotonicity(t *testing.T) {
+	equal(t, 0.0, Monotonicity(game.Cells{
+		{2, 4, 8, 16},
+		{4, 8, 16, 32},
+		{8, 16, 32, 64},
+		{16, 32, 64, 128},
+	}))
+	// the 8 breaks the increasing first row, costing the single step down
+	equal(t, -1.0, Monotonicity(game.Cells{
+		{2, 8, 4, 16},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	}))
+}
+
+func TestSmoothness(t *testing.T) {
+	equal(t, 0.0, Smoothness(game.Cells{
+		{4, 4, 0, 0},
+		{4, 0, 0, 0},
+		{0, 0, 0, 2},
+		{0, 0, 0, 0},
+	}))
+	equal(t, -4.0, Smoothness(game.Cells{
+		{2, 16, 0, 0},
+		{4, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	}))
+}
+
+func TestEmptyCells(t *testing.T) {
+	equal(t, 13.0, EmptyCells(game.Cells{
+		{2, 16, 0, 0},
+		{4, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	}))
+}
+
+func TestCornerWeight(t *testing.T) {
+	corner := CornerWeight(game.Cells{
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 1024},
+	})
+	center := CornerWeight(game.Cells{
+		{0, 0, 0, 0},
+		{0, 1024, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	equal(t, 10.0, corner)
+	equal(t, true, corner > center)
+}
+
+func TestCombine(t *testing.T) {
+	h := Combine(
+		WeightedHeuristic{Heuristic: EmptyCells, Weight: 2},
+		WeightedHeuristic{Heuristic: Smoothness, Weight: 0.5},
+	)
+	equal(t, 2*13.0+0.5*-4.0, h(game.Cells{
+		{2, 16, 0, 0},
+		{4, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	}))
+}
+
+func equal(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
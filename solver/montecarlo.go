@@ -0,0 +1,333 @@
+package solver
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+const (
+	defaultRollouts  = 100
+	defaultWinTarget = 2048
+	// rolloutBatch is the number of rollouts played from a single seed. Batches are the unit of work shared
+	// between the workers, so results do not depend on how the batches were scheduled.
+	rolloutBatch = 16
+)
+
+// MonteCarlo chooses moves by playing many games of random moves after each move, picking the move whose
+// games scored the most points on average. The games are shared between goroutines and seeded from a single
+// seed, so the same seed always gives the same result unless the time budget runs out first.
+type MonteCarlo struct {
+	rollouts  int
+	budget    time.Duration
+	workers   int
+	seed      int64
+	spawn     game.SpawnPolicy
+	winTarget uint32
+	maxCell   uint32
+}
+
+var _ Strategy = (*MonteCarlo)(nil)
+
+// RolloutStats summarises the random games played after a move
+type RolloutStats struct {
+	Direction game.Direction
+	// Rollouts is the number of games played, fewer than configured if the time budget ran out
+	Rollouts int
+	// Wins is the number of games that reached the win target
+	Wins int
+	// WinRate is the fraction of games that reached the win target
+	WinRate float64
+	// AverageScore is the mean number of points earned by the move and the game played after it
+	AverageScore float64
+}
+
+// NewMonteCarlo builds a Monte Carlo rollout player. By default it plays 100 games after each move on every
+// CPU, with game.ClassicSpawns, a win target of 2048, no time budget and a time based seed.
+func NewMonteCarlo(options ...MonteCarloOption) *MonteCarlo {
+	m := &MonteCarlo{
+		rollouts:  defaultRollouts,
+		workers:   runtime.GOMAXPROCS(0),
+		seed:      time.Now().UnixNano(),
+		spawn:     game.ClassicSpawns(),
+		winTarget: defaultWinTarget,
+		maxCell:   defaultMaxCell,
+	}
+	for _, option := range options {
+		option.apply(m)
+	}
+	return m
+}
+
+// NextMove returns the move with the highest average score
+func (m *MonteCarlo) NextMove(cells game.Cells) (game.Direction, bool) {
+	stats := m.Evaluate(cells)
+	if len(stats) == 0 {
+		return 0, false
+	}
+	return stats[0].Direction, true
+}
+
+// Evaluate plays the rollouts of every move that changes the board, returning their stats ordered best first
+func (m *MonteCarlo) Evaluate(cells game.Cells) []RolloutStats {
+	return m.Search(context.Background(), cells)
+}
+
+// Search evaluates every move like Evaluate, stopping early once ctx is done or the time budget runs out.
+// Rollouts are started for every move in turn, so the moves are played a similar number of times however
+// early the search stops.
+func (m *MonteCarlo) Search(ctx context.Context, cells game.Cells) []RolloutStats {
+	if m.budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.budget)
+		defer cancel()
+	}
+
+	type move struct {
+		cells game.Cells
+		score uint64
+	}
+	var (
+		moves []move
+		stats []RolloutStats
+	)
+	for _, direction := range Directions {
+		shifted, score, changed := cells.ShiftCapped(direction, m.maxCell)
+		if changed {
+			moves = append(moves, move{cells: shifted, score: score})
+			stats = append(stats, RolloutStats{Direction: direction})
+		}
+	}
+	if len(moves) == 0 {
+		return nil
+	}
+
+	var (
+		batches       = (m.rollouts + rolloutBatch - 1) / rolloutBatch
+		jobs          = int64(batches * len(moves))
+		next    int64 = -1
+		totals        = make([]uint64, len(moves))
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < m.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				job := atomic.AddInt64(&next, 1)
+				if job >= jobs {
+					return
+				}
+				// jobs are ordered batch first, so every move has its first batch played before any second batch
+				idx, batch := int(job)%len(moves), int(job)/len(moves)
+				n := rolloutBatch
+				if remaining := m.rollouts - batch*rolloutBatch; remaining < n {
+					n = remaining
+				}
+				rng := rand.New(rand.NewSource(m.seed + int64(stats[idx].Direction)<<32 + int64(batch)))
+				var total uint64
+				var wins int
+				for i := 0; i < n; i++ {
+					score, won := m.rollout(moves[idx].cells, rng)
+					total += moves[idx].score + score
+					if won {
+						wins++
+					}
+				}
+				mu.Lock()
+				totals[idx] += total
+				stats[idx].Rollouts += n
+				stats[idx].Wins += wins
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range stats {
+		if stats[i].Rollouts > 0 {
+			stats[i].WinRate = float64(stats[i].Wins) / float64(stats[i].Rollouts)
+			stats[i].AverageScore = float64(totals[i]) / float64(stats[i].Rollouts)
+		}
+	}
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].AverageScore > stats[j].AverageScore })
+	return stats
+}
+
+// rollout adds the spawned tiles to the cells and plays random moves until none remain, returning the
+// points earned and whether the win target was reached
+func (m *MonteCarlo) rollout(cells game.Cells, rng *rand.Rand) (score uint64, won bool) {
+	var (
+		options [len(Directions)]game.Cells
+		scores  [len(Directions)]uint64
+	)
+	cells = cells.Clone()
+	for {
+		m.addSpawns(cells, rng)
+		n := 0
+		for _, direction := range Directions {
+			if shifted, points, changed := cells.ShiftCapped(direction, m.maxCell); changed {
+				options[n], scores[n] = shifted, points
+				n++
+			}
+		}
+		if n == 0 {
+			return score, cells.MaxValue() >= m.winTarget
+		}
+		choice := rng.Intn(n)
+		cells = options[choice]
+		score += scores[choice]
+	}
+}
+
+// addSpawns places the tiles added after a move at random, as the game would
+func (m *MonteCarlo) addSpawns(cells game.Cells, rng *rand.Rand) {
+	for i := 0; i < m.spawn.PerMove; i++ {
+		empty := cells.EmptyPositions()
+		if len(empty) == 0 {
+			return
+		}
+		pos := empty[rng.Intn(len(empty))]
+		cells[pos.Row][pos.Col] = drawSpawn(m.spawn, rng)
+	}
+}
+
+// drawSpawn picks a value the policy spawns according to its weights
+func drawSpawn(policy game.SpawnPolicy, rng *rand.Rand) uint32 {
+	total := 0
+	for _, w := range policy.Weights {
+		total += w.Weight
+	}
+	n := rng.Intn(total)
+	for _, w := range policy.Weights {
+		if n < w.Weight {
+			return w.Value
+		}
+		n -= w.Weight
+	}
+	return policy.Weights[len(policy.Weights)-1].Value
+}
+
+// MonteCarloOption configures a MonteCarlo player
+type MonteCarloOption interface {
+	apply(m *MonteCarlo)
+}
+
+// WithRollouts sets the number of random games played after each move
+func WithRollouts(rollouts int) MonteCarloOption {
+	return rolloutsOption{rollouts: rollouts}
+}
+
+type rolloutsOption struct {
+	rollouts int
+}
+
+func (o rolloutsOption) apply(m *MonteCarlo) {
+	if o.rollouts < 1 {
+		panic("WithRollouts: rollouts must be at least 1")
+	}
+	m.rollouts = o.rollouts
+}
+
+// WithTimeBudget limits the time spent choosing each move. Moves are chosen from the games finished when the
+// budget runs out, which depends on the speed of the machine. By default there is no limit.
+func WithTimeBudget(budget time.Duration) MonteCarloOption {
+	return timeBudgetOption{budget: budget}
+}
+
+type timeBudgetOption struct {
+	budget time.Duration
+}
+
+func (o timeBudgetOption) apply(m *MonteCarlo) {
+	if o.budget < 0 {
+		panic("WithTimeBudget: budget must not be negative")
+	}
+	m.budget = o.budget
+}
+
+// WithWorkers sets the number of goroutines playing games, by default one for each CPU
+func WithWorkers(workers int) MonteCarloOption {
+	return workersOption{workers: workers}
+}
+
+type workersOption struct {
+	workers int
+}
+
+func (o workersOption) apply(m *MonteCarlo) {
+	if o.workers < 1 {
+		panic("WithWorkers: workers must be at least 1")
+	}
+	m.workers = o.workers
+}
+
+// WithSeed sets the seed of the random games. The same seed chooses the same moves for the same board,
+// whatever the number of workers.
+func WithSeed(seed int64) MonteCarloOption {
+	return seedOption{seed: seed}
+}
+
+type seedOption struct {
+	seed int64
+}
+
+func (o seedOption) apply(m *MonteCarlo) {
+	m.seed = o.seed
+}
+
+// WithRolloutSpawnPolicy sets the tiles added after each move of the random games to those of the game's policy
+func WithRolloutSpawnPolicy(policy game.SpawnPolicy) MonteCarloOption {
+	return rolloutSpawnPolicyOption{policy: policy}
+}
+
+type rolloutSpawnPolicyOption struct {
+	policy game.SpawnPolicy
+}
+
+func (o rolloutSpawnPolicyOption) apply(m *MonteCarlo) {
+	if err := o.policy.Validate(); err != nil {
+		panic("WithRolloutSpawnPolicy: " + err.Error())
+	}
+	m.spawn = o.policy
+}
+
+// WithWinTarget sets the tile value a random game must reach to count as a win
+func WithWinTarget(target uint32) MonteCarloOption {
+	return winTargetOption{target: target}
+}
+
+type winTargetOption struct {
+	target uint32
+}
+
+func (o winTargetOption) apply(m *MonteCarlo) {
+	if o.target < 4 || o.target&(o.target-1) != 0 {
+		panic("WithWinTarget: target must be a power of two of at least 4")
+	}
+	m.winTarget = o.target
+}
+
+// WithRolloutMaxCell sets the largest value a cell can hold to the game's Controller.MaxCell, so the moves and
+// random games only merge cells the game would. By default cells merge up to 2^31.
+func WithRolloutMaxCell(maxCell uint32) MonteCarloOption {
+	return rolloutMaxCellOption{maxCell: maxCell}
+}
+
+type rolloutMaxCellOption struct {
+	maxCell uint32
+}
+
+func (o rolloutMaxCellOption) apply(m *MonteCarlo) {
+	if o.maxCell < 4 || o.maxCell&(o.maxCell-1) != 0 {
+		panic("WithRolloutMaxCell: max cell must be a power of two of at least 4")
+	}
+	m.maxCell = o.maxCell
+}
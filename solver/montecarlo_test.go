@@ -0,0 +1,97 @@
+package solver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+func TestMonteCarlo_Evaluate(t *testing.T) {
+	cells := game.Cells{
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{2, 0, 0, 0},
+		{512, 512, 4, 2},
+	}
+	stats := NewMonteCarlo(WithSeed(1), WithRollouts(50), WithWorkers(1)).Evaluate(cells)
+	// every move but down changes the board
+	equal(t, 3, len(stats))
+	for i, s := range stats {
+		equal(t, 50, s.Rollouts)
+		if i > 0 {
+			equal(t, true, stats[i-1].AverageScore >= s.AverageScore)
+		}
+	}
+	// merging the two 512 tiles earns at least 1024 points straight away
+	equal(t, true, stats[0].Direction == game.DirectionLeft || stats[0].Direction == game.DirectionRight)
+	equal(t, true, stats[0].AverageScore > 1024)
+
+	// the same seed gives the same result however the games are shared between workers
+	equal(t, stats, NewMonteCarlo(WithSeed(1), WithRollouts(50), WithWorkers(4)).Evaluate(cells))
+
+	// a board with no moves remaining
+	equal(t, 0, len(NewMonteCarlo().Evaluate(game.Cells{
+		{2, 4},
+		{4, 2},
+	})))
+}
+
+func TestMonteCarlo_maxCell(t *testing.T) {
+	cells := game.Cells{
+		{1 << 15, 1 << 15},
+		{2, 4},
+	}
+	// the 32768 tiles can only merge beyond the cap, leaving no move once capped
+	equal(t, 2, len(NewMonteCarlo(WithSeed(1), WithRollouts(1)).Evaluate(cells)))
+	equal(t, 0, len(NewMonteCarlo(WithSeed(1), WithRollouts(1), WithRolloutMaxCell(1<<15)).Evaluate(cells)))
+}
+
+func TestMonteCarlo_winRate(t *testing.T) {
+	stats := NewMonteCarlo(WithSeed(1), WithRollouts(20), WithWinTarget(8)).Evaluate(game.Cells{
+		{8, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	for _, s := range stats {
+		equal(t, 20, s.Wins)
+		equal(t, 1.0, s.WinRate)
+	}
+	stats = NewMonteCarlo(WithSeed(1), WithRollouts(20)).Evaluate(game.Cells{
+		{8, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	for _, s := range stats {
+		equal(t, 0.0, s.WinRate)
+	}
+}
+
+func TestMonteCarlo_timeBudget(t *testing.T) {
+	m := NewMonteCarlo(WithRollouts(1000000), WithTimeBudget(10*time.Millisecond))
+	start := time.Now()
+	stats := m.Evaluate(game.Cells{
+		{2, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	equal(t, true, time.Since(start) < time.Second)
+	equal(t, 2, len(stats))
+	for _, s := range stats {
+		equal(t, true, s.Rollouts < 1000000)
+	}
+}
+
+func TestMonteCarlo_playsGame(t *testing.T) {
+	gc := game.NewController(game.WithSeed(1))
+	m := NewMonteCarlo(WithSeed(1), WithRollouts(10))
+	for i := 0; i < 200 && !gc.Lost(); i++ {
+		direction, ok := m.NextMove(gc.GetCells())
+		equal(t, true, ok)
+		equal(t, true, gc.Shift(direction))
+	}
+	equal(t, true, gc.GetCells().MaxValue() >= 128)
+}
@@ -0,0 +1,80 @@
+package solver
+
+import (
+	"math/rand"
+
+	"github.com/brandenc40/2048/game"
+)
+
+// Random chooses any move that changes the board, a baseline for the other strategies. A Random is not safe
+// for concurrent use.
+type Random struct {
+	rng *rand.Rand
+}
+
+var _ Strategy = (*Random)(nil)
+
+// NewRandom builds a random player, the same seed always choosing the same moves for the same boards
+func NewRandom(seed int64) *Random {
+	return &Random{rng: rand.New(rand.NewSource(seed))}
+}
+
+// NextMove returns a random move that changes the board
+func (r *Random) NextMove(cells game.Cells) (game.Direction, bool) {
+	var moves []game.Direction
+	for _, direction := range Directions {
+		if _, _, changed := cells.Shift(direction); changed {
+			moves = append(moves, direction)
+		}
+	}
+	if len(moves) == 0 {
+		return 0, false
+	}
+	return moves[r.rng.Intn(len(moves))], true
+}
+
+// Greedy chooses the move that earns the most points straight away, preferring the earlier of Directions
+// when moves earn the same
+type Greedy struct{}
+
+var _ Strategy = Greedy{}
+
+// NewGreedy builds a greedy player
+func NewGreedy() Greedy { return Greedy{} }
+
+// NextMove returns the move that earns the most points
+func (Greedy) NextMove(cells game.Cells) (game.Direction, bool) {
+	var (
+		best      game.Direction
+		bestScore uint64
+		ok        bool
+	)
+	for _, direction := range Directions {
+		if _, score, changed := cells.Shift(direction); changed && (!ok || score > bestScore) {
+			best, bestScore, ok = direction, score, true
+		}
+	}
+	return best, ok
+}
+
+// cornerOrder keeps the largest tiles in the bottom left corner, only moving up when nothing else is possible
+var cornerOrder = [...]game.Direction{game.DirectionDown, game.DirectionLeft, game.DirectionRight, game.DirectionUp}
+
+// Corner plays the well known corner strategy, moving down or left whenever it can, then right and as a last
+// resort up
+type Corner struct{}
+
+var _ Strategy = Corner{}
+
+// NewCorner builds a corner player
+func NewCorner() Corner { return Corner{} }
+
+// NextMove returns the first of down, left, right and up that changes the board
+func (Corner) NextMove(cells game.Cells) (game.Direction, bool) {
+	for _, direction := range cornerOrder {
+		if _, _, changed := cells.Shift(direction); changed {
+			return direction, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,67 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/brandenc40/2048/game"
+)
+
+func TestRandom_NextMove(t *testing.T) {
+	cells := game.Cells{
+		{2, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	}
+	seen := map[game.Direction]bool{}
+	r := NewRandom(1)
+	for i := 0; i < 50; i++ {
+		direction, ok := r.NextMove(cells)
+		equal(t, true, ok)
+		seen[direction] = true
+	}
+	// the tile is already in the top left corner, so only right and down change the board
+	equal(t, map[game.Direction]bool{game.DirectionRight: true, game.DirectionDown: true}, seen)
+
+	// the same seed chooses the same moves
+	a, b := NewRandom(7), NewRandom(7)
+	for i := 0; i < 20; i++ {
+		da, _ := a.NextMove(cells)
+		db, _ := b.NextMove(cells)
+		equal(t, da, db)
+	}
+
+	_, ok := r.NextMove(game.Cells{{2, 4}, {4, 2}})
+	equal(t, false, ok)
+}
+
+func TestGreedy_NextMove(t *testing.T) {
+	direction, ok := NewGreedy().NextMove(game.Cells{
+		{2, 0, 0, 0},
+		{2, 0, 0, 0},
+		{0, 0, 0, 0},
+		{8, 8, 0, 0},
+	})
+	equal(t, true, ok)
+	equal(t, game.DirectionLeft, direction)
+
+	_, ok = NewGreedy().NextMove(game.Cells{{2, 4}, {4, 2}})
+	equal(t, false, ok)
+}
+
+func TestCorner_NextMove(t *testing.T) {
+	tests := []struct {
+		cells    game.Cells
+		expected game.Direction
+	}{
+		{game.Cells{{2, 0}, {0, 0}}, game.DirectionDown},
+		{game.Cells{{0, 0}, {0, 2}}, game.DirectionLeft},
+		{game.Cells{{0, 0}, {2, 4}}, game.DirectionUp},
+		{game.Cells{{0, 4}, {2, 8}}, game.DirectionLeft},
+	}
+	for _, tt := range tests {
+		direction, ok := NewCorner().NextMove(tt.cells)
+		equal(t, true, ok)
+		equal(t, tt.expected, direction)
+	}
+}
@@ -0,0 +1,30 @@
+// Package solver chooses moves for a 2048 game board. Strategies work on game.Cells so they can be used
+// with any game.Controller, or on boards that are not part of a running game.
+package solver
+
+import "github.com/brandenc40/2048/game"
+
+// Strategy chooses the next move for a game board
+type Strategy interface {
+	// NextMove returns the Direction to shift the cells in. False is returned if no move changes the board
+	NextMove(cells game.Cells) (direction game.Direction, ok bool)
+}
+
+// Directions lists every Direction a board can be shifted in
+var Directions = [...]game.Direction{game.DirectionLeft, game.DirectionUp, game.DirectionRight, game.DirectionDown}
+
+// DirectionName returns a lower case name for the direction, such as "left"
+func DirectionName(direction game.Direction) string {
+	switch direction {
+	case game.DirectionLeft:
+		return "left"
+	case game.DirectionUp:
+		return "up"
+	case game.DirectionRight:
+		return "right"
+	case game.DirectionDown:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,51 @@
+package sshserver
+
+import (
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/terminalui"
+)
+
+// Option configures a Server built by New
+type Option interface {
+	apply(s *Server)
+}
+
+// WithStatsDir keeps the statistics of each player in a file in dir named after their public key. By default
+// statistics are only kept until the server stops.
+func WithStatsDir(dir string) Option {
+	return statsDirOption{dir: dir}
+}
+
+type statsDirOption struct {
+	dir string
+}
+
+func (o statsDirOption) apply(s *Server) {
+	s.statsDir = o.dir
+}
+
+// WithGameOptions sets the options used to build the game for each session
+func WithGameOptions(options ...game.Option) Option {
+	return gameOptionsOption{options: options}
+}
+
+type gameOptionsOption struct {
+	options []game.Option
+}
+
+func (o gameOptionsOption) apply(s *Server) {
+	s.gameOptions = append(s.gameOptions, o.options...)
+}
+
+// WithUIOptions sets the terminal UI options of each session, such as the output mode and animations
+func WithUIOptions(options ...terminalui.Option) Option {
+	return uiOptionsOption{options: options}
+}
+
+type uiOptionsOption struct {
+	options []terminalui.Option
+}
+
+func (o uiOptionsOption) apply(s *Server) {
+	s.uiOptions = append(s.uiOptions, o.options...)
+}
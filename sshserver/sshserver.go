@@ -0,0 +1,128 @@
+// Package sshserver hosts the terminal game over SSH. Every session plays its own game, drawn by the terminal
+// UI to the session's terminal, and players who sign in with a public key keep their statistics between
+// visits.
+package sshserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"sync"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/stats"
+	"github.com/brandenc40/2048/terminalui"
+	"github.com/gliderlabs/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// Server accepts SSH connections and runs a game for every session with a terminal
+type Server struct {
+	srv         *ssh.Server
+	statsDir    string
+	gameOptions []game.Option
+	uiOptions   []terminalui.Option
+
+	mu     sync.Mutex
+	stores map[string]*stats.Store
+}
+
+// New builds a server that listens on addr and identifies itself with the host key
+func New(addr string, hostKey gossh.Signer, options ...Option) *Server {
+	s := &Server{stores: make(map[string]*stats.Store)}
+	for _, option := range options {
+		option.apply(s)
+	}
+	s.srv = &ssh.Server{
+		Addr:    addr,
+		Handler: s.handle,
+		// any key is accepted, it only identifies the player's statistics
+		PublicKeyHandler: func(ssh.Context, ssh.PublicKey) bool { return true },
+		// players without a key can still play as a guest
+		KeyboardInteractiveHandler: func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool { return true },
+	}
+	s.srv.AddHostKey(hostKey)
+	return s
+}
+
+// ListenAndServe accepts connections on the server address until the server is shut down
+func (s *Server) ListenAndServe() error {
+	return s.srv.ListenAndServe()
+}
+
+// Serve accepts connections on the listener until the server is shut down
+func (s *Server) Serve(l net.Listener) error {
+	return s.srv.Serve(l)
+}
+
+// Shutdown stops accepting connections and waits for the games in progress to end. Any connections still
+// open when ctx is done are closed.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.srv.Shutdown(ctx)
+	if ctx.Err() != nil {
+		// end the games still in progress, the listeners are already closed
+		_ = s.srv.Close()
+	}
+	return err
+}
+
+// handle plays a game on the session's terminal until the player quits or disconnects
+func (s *Server) handle(sess ssh.Session) {
+	pty, windows, ok := sess.Pty()
+	if !ok {
+		fmt.Fprintln(sess.Stderr(), "2048 needs a terminal, connect with ssh -t")
+		_ = sess.Exit(1)
+		return
+	}
+	screen := terminalui.NewANSIScreen(sess, pty.Window.Width, pty.Window.Height)
+	go func() {
+		// closed when the session ends
+		for window := range windows {
+			screen.Resize(window.Width, window.Height)
+		}
+	}()
+
+	options := append([]terminalui.Option{terminalui.WithScreen(screen)}, s.uiOptions...)
+	if key := sess.PublicKey(); key != nil {
+		store, err := s.statsStore(key)
+		if err != nil {
+			fmt.Fprintln(sess.Stderr(), err)
+			_ = sess.Exit(1)
+			return
+		}
+		options = append(options, terminalui.WithStats(store))
+	}
+
+	err := terminalui.Run(game.NewController(s.gameOptions...), options...)
+	if err != nil && !errors.Is(err, io.EOF) {
+		_ = sess.Exit(1)
+		return
+	}
+	_ = sess.Exit(0)
+}
+
+// statsStore returns the statistics of the player with the public key, shared by all of their sessions
+func (s *Server) statsStore(key ssh.PublicKey) (*stats.Store, error) {
+	sum := sha256.Sum256(key.Marshal())
+	id := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if store, ok := s.stores[id]; ok {
+		return store, nil
+	}
+	store := stats.NewStore()
+	if s.statsDir != "" {
+		var err error
+		if store, err = stats.Open(filepath.Join(s.statsDir, id+".json")); err != nil {
+			return nil, err
+		}
+	}
+	s.stores[id] = store
+	return store, nil
+}
@@ -0,0 +1,190 @@
+package sshserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/stats"
+	"github.com/brandenc40/2048/terminalui"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// syncBuffer collects the output of a session while the test reads it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func startServer(t *testing.T, options ...Option) (addr string) {
+	t.Helper()
+	hostKey, err := LoadHostKey(filepath.Join(t.TempDir(), "host_key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := New(l.Addr().String(), hostKey, options...)
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	})
+	return l.Addr().String()
+}
+
+func dial(t *testing.T, addr string, key ed25519.PrivateKey) *gossh.Client {
+	t.Helper()
+	signer, err := gossh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := gossh.Dial("tcp", addr, &gossh.ClientConfig{
+		User:            "player",
+		Auth:            []gossh.AuthMethod{gossh.PublicKeys(signer)},
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func waitFor(t *testing.T, out *syncBuffer, text string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), text) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %q", text)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServer_session(t *testing.T) {
+	addr := startServer(t,
+		WithGameOptions(game.WithSeed(1)),
+		WithUIOptions(terminalui.WithoutAnimations()),
+	)
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	client := dial(t, addr, key)
+	defer client.Close()
+
+	sess, err := client.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sess.Close()
+	out := &syncBuffer{}
+	sess.Stdout = out
+	stdin, err := sess.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.RequestPty("xterm", 40, 120, gossh.TerminalModes{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.Shell(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, out, "Current Score: 0")
+
+	// the game is drawn again at the new size
+	before := len(out.String())
+	if err := sess.WindowChange(50, 130); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, out, out.String()[:before]+"\x1b[")
+
+	// quit with escape
+	if _, err := stdin.Write([]byte{0x1b}); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, nil, sess.Wait())
+}
+
+func TestServer_noTerminal(t *testing.T) {
+	addr := startServer(t)
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	client := dial(t, addr, key)
+	defer client.Close()
+
+	sess, err := client.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sess.Close()
+	out, err := sess.CombinedOutput("")
+	var exitErr *gossh.ExitError
+	equal(t, true, errors.As(err, &exitErr))
+	equal(t, 1, exitErr.ExitStatus())
+	equal(t, true, strings.Contains(string(out), "needs a terminal"))
+}
+
+func TestServer_statsStore(t *testing.T) {
+	dir := t.TempDir()
+	srv := New("", nil, WithStatsDir(dir))
+	keyA, _, _ := ed25519.GenerateKey(rand.Reader)
+	keyB, _, _ := ed25519.GenerateKey(rand.Reader)
+	pubA, _ := gossh.NewPublicKey(keyA)
+	pubB, _ := gossh.NewPublicKey(keyB)
+
+	storeA, err := srv.statsStore(pubA)
+	equal(t, nil, err)
+	again, err := srv.statsStore(pubA)
+	equal(t, nil, err)
+	storeB, err := srv.statsStore(pubB)
+	equal(t, nil, err)
+	equal(t, true, storeA == again)
+	equal(t, false, storeA == storeB)
+
+	gc := game.NewController(game.WithSeed(1))
+	gc.Shift(game.DirectionLeft)
+	gc.Shift(game.DirectionUp)
+	equal(t, nil, storeA.Record(stats.NewGameRecord(gc)))
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	equal(t, 1, len(files))
+}
+
+func TestLoadHostKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "host_key")
+	generated, err := LoadHostKey(path)
+	equal(t, nil, err)
+	loaded, err := LoadHostKey(path)
+	equal(t, nil, err)
+	equal(t, generated.PublicKey().Marshal(), loaded.PublicKey().Marshal())
+	equal(t, "ssh-ed25519", loaded.PublicKey().Type())
+}
+
+func equal(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,159 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+const storeVersion = 1
+
+// GameRecord is the outcome of a single finished game
+type GameRecord struct {
+	Finished    time.Time `json:"finished"`
+	Score       uint64    `json:"score"`
+	HighestTile uint32    `json:"highest_tile"`
+	Moves       int       `json:"moves"`
+	Won         bool      `json:"won"`
+	Rows        int       `json:"rows"`
+	Cols        int       `json:"cols"`
+}
+
+// NewGameRecord builds a record of the current state of the game
+func NewGameRecord(gc game.Controller) GameRecord {
+	cells := gc.GetCells()
+	return GameRecord{
+		Finished:    time.Now(),
+		Score:       gc.GetScore(),
+		HighestTile: cells.MaxValue(),
+		Moves:       gc.GetMoveCount(),
+		Won:         gc.Won(),
+		Rows:        cells.Rows(),
+		Cols:        cells.Cols(),
+	}
+}
+
+// Summary of all recorded games
+type Summary struct {
+	GamesPlayed   int
+	GamesWon      int
+	BestScore     uint64
+	HighestTile   uint32
+	AverageScore  float64
+	CurrentStreak int
+	LongestStreak int
+}
+
+// Store keeps the history of finished games. Stores opened from a file save every new record to it.
+type Store struct {
+	mu    sync.Mutex
+	path  string
+	games []GameRecord
+}
+
+type storeFile struct {
+	Version int          `json:"version"`
+	Games   []GameRecord `json:"games"`
+}
+
+// NewStore builds a store that only keeps records in memory
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Open loads the store saved at path. A new empty store is returned if the file does not exist yet.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading stats: %w", err)
+	}
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decoding stats %s: %w", path, err)
+	}
+	if file.Version != storeVersion {
+		return nil, fmt.Errorf("stats %s: unsupported version %d", path, file.Version)
+	}
+	s.games = file.Games
+	return s, nil
+}
+
+// Record adds a finished game to the store, saving the store if it was opened from a file
+func (s *Store) Record(record GameRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.games = append(s.games, record)
+	return s.save()
+}
+
+// History returns every recorded game, oldest first
+func (s *Store) History() []GameRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]GameRecord(nil), s.games...)
+}
+
+// Summary of all recorded games
+func (s *Store) Summary() Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var (
+		summary    Summary
+		totalScore float64
+	)
+	for _, g := range s.games {
+		summary.GamesPlayed++
+		totalScore += float64(g.Score)
+		if g.Score > summary.BestScore {
+			summary.BestScore = g.Score
+		}
+		if g.HighestTile > summary.HighestTile {
+			summary.HighestTile = g.HighestTile
+		}
+		if g.Won {
+			summary.GamesWon++
+			summary.CurrentStreak++
+			if summary.CurrentStreak > summary.LongestStreak {
+				summary.LongestStreak = summary.CurrentStreak
+			}
+		} else {
+			summary.CurrentStreak = 0
+		}
+	}
+	if summary.GamesPlayed > 0 {
+		summary.AverageScore = totalScore / float64(summary.GamesPlayed)
+	}
+	return summary
+}
+
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(storeFile{Version: storeVersion, Games: s.games})
+	if err != nil {
+		return fmt.Errorf("encoding stats: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("creating stats directory: %w", err)
+	}
+	// write to a temporary file first so a failed write never corrupts the previous stats
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing stats: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("writing stats: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+func TestStore_Summary(t *testing.T) {
+	s := NewStore()
+	equal(t, Summary{}, s.Summary())
+
+	for _, record := range []GameRecord{
+		{Score: 100, HighestTile: 64},
+		{Score: 3000, HighestTile: 2048, Won: true},
+		{Score: 5000, HighestTile: 2048, Won: true},
+		{Score: 200, HighestTile: 128},
+		{Score: 4000, HighestTile: 4096, Won: true},
+	} {
+		equal(t, nil, s.Record(record))
+	}
+	equal(t, Summary{
+		GamesPlayed:   5,
+		GamesWon:      3,
+		BestScore:     5000,
+		HighestTile:   4096,
+		AverageScore:  2460,
+		CurrentStreak: 1,
+		LongestStreak: 2,
+	}, s.Summary())
+	equal(t, 5, len(s.History()))
+}
+
+func TestStore_persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats", "stats.json")
+	s, err := Open(path)
+	equal(t, nil, err)
+	equal(t, 0, len(s.History()))
+
+	record := GameRecord{Finished: time.Unix(1600000000, 0).UTC(), Score: 42, HighestTile: 8, Moves: 10, Rows: 4, Cols: 4}
+	equal(t, nil, s.Record(record))
+
+	reopened, err := Open(path)
+	equal(t, nil, err)
+	equal(t, []GameRecord{record}, reopened.History())
+}
+
+func TestNewGameRecord(t *testing.T) {
+	gc := game.NewController(game.WithSize(3, 5))
+	gc.Shift(game.DirectionLeft)
+	gc.Shift(game.DirectionRight)
+
+	record := NewGameRecord(gc)
+	equal(t, gc.GetScore(), record.Score)
+	equal(t, gc.GetCells().MaxValue(), record.HighestTile)
+	equal(t, gc.GetMoveCount(), record.Moves)
+	equal(t, 3, record.Rows)
+	equal(t, 5, record.Cols)
+}
+
+func equal(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,143 @@
+package terminalui
+
+import (
+	"math"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+const (
+	defaultAnimationDuration = 120 * time.Millisecond
+	animationFrameInterval   = 15 * time.Millisecond
+	// share of the animation frames spent sliding tiles, the rest show merges popping and spawns fading in
+	slideFrameShare = 0.6
+)
+
+// animator steps through the frames of a shift animation
+type animator struct {
+	slideFrames int
+	popFrames   int
+	frame       int
+	result      game.ShiftResult
+	ticker      *time.Ticker
+}
+
+// newAnimator builds an animator that plays each shift over the given duration. Animations are disabled
+// if the duration is not positive.
+func newAnimator(duration time.Duration) animator {
+	frames := int(duration / animationFrameInterval)
+	if frames <= 0 {
+		return animator{}
+	}
+	slideFrames := int(float64(frames) * slideFrameShare)
+	if slideFrames < 1 {
+		slideFrames = 1
+	}
+	popFrames := frames - slideFrames
+	if popFrames < 1 {
+		popFrames = 1
+	}
+	return animator{slideFrames: slideFrames, popFrames: popFrames}
+}
+
+func (a *animator) enabled() bool { return a.slideFrames > 0 }
+func (a *animator) running() bool { return a.ticker != nil }
+
+// start playing the animation for the shift result from the first frame
+func (a *animator) start(res game.ShiftResult) {
+	a.stop()
+	a.result = res
+	a.frame = 0
+	a.ticker = time.NewTicker(animationFrameInterval)
+}
+
+func (a *animator) stop() {
+	if a.ticker != nil {
+		a.ticker.Stop()
+		a.ticker = nil
+	}
+}
+
+// tick returns the channel that fires when the next frame is due, or nil if no animation is running
+func (a *animator) tick() <-chan time.Time {
+	if a.ticker == nil {
+		return nil
+	}
+	return a.ticker.C
+}
+
+// advance to the next frame, returning false and stopping once the last frame has been shown
+func (a *animator) advance() bool {
+	a.frame++
+	if a.frame >= a.slideFrames+a.popFrames {
+		a.stop()
+		return false
+	}
+	return true
+}
+
+// sliding returns true and the progress of the slide, between 0 and 1, if tiles are still sliding
+func (a *animator) sliding() (bool, float64) {
+	if a.frame < a.slideFrames {
+		return true, float64(a.frame+1) / float64(a.slideFrames)
+	}
+	return false, 1
+}
+
+// popProgress returns the progress, between 0 and 1, of merged tiles popping and new tiles fading in
+func (a *animator) popProgress() float64 {
+	return float64(a.frame-a.slideFrames+1) / float64(a.popFrames)
+}
+
+func (u *ui) drawAnimationFrame() {
+	if u.layout.tooSmall {
+		u.drawTooSmall()
+		return
+	}
+	u.drawGameBackground()
+	if sliding, progress := u.animator.sliding(); sliding {
+		u.drawSlideFrame(progress)
+	} else {
+		u.drawPopFrame(u.animator.popProgress())
+	}
+	u.drawScore()
+	u.flush()
+}
+
+// drawSlideFrame draws every tile part way between its position before and after the shift
+func (u *ui) drawSlideFrame(progress float64) {
+	cells := u.gc.GetCells()
+	for rowIdx := range cells {
+		for colIdx := range cells[rowIdx] {
+			u.drawGameCell(colIdx, rowIdx, 0)
+		}
+	}
+	for _, move := range u.animator.result.Moves {
+		fromX, fromY := u.cellOrigin(move.From.Col, move.From.Row)
+		toX, toY := u.cellOrigin(move.To.Col, move.To.Row)
+		u.drawTile(lerp(fromX, toX, progress), lerp(fromY, toY, progress), 0, move.Value)
+	}
+}
+
+// drawPopFrame draws the final board with merged tiles briefly enlarged and spawned tiles growing into place
+func (u *ui) drawPopFrame(progress float64) {
+	u.drawGameCells()
+	if progress >= 1 {
+		return
+	}
+	for _, merged := range u.animator.result.Merges {
+		x, y := u.cellOrigin(merged.Col, merged.Row)
+		u.drawTile(x, y, 1, merged.Value)
+	}
+	shrink := int((1 - progress) * float64(u.layout.tile.height))
+	for _, spawned := range u.animator.result.Spawned {
+		x, y := u.cellOrigin(spawned.Col, spawned.Row)
+		u.drawGameCell(spawned.Col, spawned.Row, 0)
+		u.drawTile(x, y, -shrink, spawned.Value)
+	}
+}
+
+func lerp(from, to int, progress float64) int {
+	return from + int(math.Round(float64(to-from)*progress))
+}
@@ -0,0 +1,325 @@
+package terminalui
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// errScreenClosed is returned by a screen once it has been closed
+var errScreenClosed = errors.New("screen closed")
+
+const (
+	// escapeTimeout is how long the rest of an escape sequence may take to arrive, after which the input
+	// waiting for it is decoded as it is, a lone escape becoming the escape key
+	escapeTimeout = 50 * time.Millisecond
+	// maxSequenceLen bounds the input held back waiting for the end of an escape sequence
+	maxSequenceLen = 32
+)
+
+// unknownCell marks a cell whose contents on the terminal are unknown, so it is always drawn
+var unknownCell = ScreenCell{Ch: -1}
+
+// ANSIScreen is a terminal connected over a stream, such as an SSH session. It draws with ANSI escape
+// sequences and decodes key presses read from the stream, so every connection can play its own game.
+type ANSIScreen struct {
+	rw io.ReadWriter
+	mu sync.Mutex
+	// back holds the cells drawn since the last flush, front the cells shown on the terminal
+	back, front cellBuffer
+	events      chan Event
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+var _ Screen = (*ANSIScreen)(nil)
+
+// NewANSIScreen builds a screen of the given size that draws to and reads from rw
+func NewANSIScreen(rw io.ReadWriter, width, height int) *ANSIScreen {
+	s := &ANSIScreen{
+		rw:     rw,
+		events: make(chan Event),
+		closed: make(chan struct{}),
+	}
+	s.resize(width, height)
+	return s
+}
+
+// Init switches the terminal to its alternate screen and starts reading key presses
+func (s *ANSIScreen) Init() error {
+	// alternate screen, hide the cursor and clear
+	if _, err := io.WriteString(s.rw, "\x1b[?1049h\x1b[?25l\x1b[0m\x1b[2J"); err != nil {
+		return err
+	}
+	go s.readInput()
+	return nil
+}
+
+// Close restores the terminal and stops reading input. PollEvent returns an error event once closed.
+func (s *ANSIScreen) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_, _ = io.WriteString(s.rw, "\x1b[0m\x1b[2J\x1b[?25h\x1b[?1049l")
+	})
+}
+
+// SetOutputMode has no effect, colors are sent to the terminal as they are drawn
+func (s *ANSIScreen) SetOutputMode(OutputMode) {}
+
+func (s *ANSIScreen) Size() (width, height int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.back.width, s.back.height
+}
+
+// Resize changes the size of the screen after the terminal window was resized, sending a resize event so
+// the game is drawn again
+func (s *ANSIScreen) Resize(width, height int) {
+	s.mu.Lock()
+	s.resize(width, height)
+	s.mu.Unlock()
+	s.send(Event{Type: EventResize, Width: width, Height: height})
+}
+
+// resize keeps the cells that still fit on the screen and forces the next flush to draw everything
+func (s *ANSIScreen) resize(width, height int) {
+	s.back.resize(width, height)
+	s.front.resize(width, height)
+	s.front.fill(unknownCell)
+}
+
+func (s *ANSIScreen) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.back.fill(blankCell)
+}
+
+func (s *ANSIScreen) SetCell(x, y int, ch rune, fg, bg Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.back.set(x, y, ScreenCell{Ch: ch, Fg: fg, Bg: bg})
+}
+
+// Flush writes the cells that changed since the last flush to the terminal
+func (s *ANSIScreen) Flush() error {
+	select {
+	case <-s.closed:
+		return errScreenClosed
+	default:
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		buf              bytes.Buffer
+		cursorX, cursorY = -1, -1
+		last             = unknownCell
+	)
+	for y := 0; y < s.back.height; y++ {
+		for x := 0; x < s.back.width; {
+			cell := s.back.get(x, y)
+			width := runewidth.RuneWidth(cell.Ch)
+			if width < 1 || x+width > s.back.width {
+				cell.Ch, width = ' ', 1
+			}
+			if cell == s.front.get(x, y) {
+				x += width
+				continue
+			}
+			s.front.set(x, y, cell)
+			if x != cursorX || y != cursorY {
+				buf.WriteString("\x1b[" + strconv.Itoa(y+1) + ";" + strconv.Itoa(x+1) + "H")
+			}
+			if last == unknownCell || cell.Fg != last.Fg || cell.Bg != last.Bg {
+				buf.WriteString(sgr(cell.Fg, cell.Bg))
+				last = cell
+			}
+			buf.WriteRune(cell.Ch)
+			x += width
+			cursorX, cursorY = x, y
+		}
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	_, err := s.rw.Write(buf.Bytes())
+	return err
+}
+
+func (s *ANSIScreen) PollEvent() Event {
+	select {
+	case ev := <-s.events:
+		return ev
+	case <-s.closed:
+		return Event{Type: EventError, Err: errScreenClosed}
+	}
+}
+
+// send delivers an event to PollEvent, dropping it if the screen is closed first
+func (s *ANSIScreen) send(ev Event) bool {
+	select {
+	case s.events <- ev:
+		return true
+	case <-s.closed:
+		return false
+	}
+}
+
+// readInput decodes key presses until the stream fails or the screen is closed. An escape sequence split
+// between reads, as happens on slow connections, is held back until the rest of it arrives.
+func (s *ANSIScreen) readInput() {
+	var (
+		reads   = make(chan []byte)
+		readErr = make(chan error, 1)
+		pending []byte
+		timeout <-chan time.Time
+	)
+	go func() {
+		for {
+			buf := make([]byte, 256)
+			n, err := s.rw.Read(buf)
+			if n > 0 {
+				select {
+				case reads <- buf[:n]:
+				case <-s.closed:
+					return
+				}
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+	// decode sends the keys in the input, returning false once the screen is closed
+	decode := func(data []byte, final bool) bool {
+		var events []Event
+		events, pending = decodeKeys(data, final)
+		for _, ev := range events {
+			if !s.send(ev) {
+				return false
+			}
+		}
+		timeout = nil
+		if len(pending) > 0 {
+			timeout = time.After(escapeTimeout)
+		}
+		return true
+	}
+	for {
+		select {
+		case data := <-reads:
+			if !decode(append(pending, data...), false) {
+				return
+			}
+		case <-timeout:
+			if !decode(pending, true) {
+				return
+			}
+		case err := <-readErr:
+			if decode(pending, true) {
+				s.send(Event{Type: EventError, Err: err})
+			}
+			return
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+var escapeKeys = map[byte]Key{
+	'A': KeyArrowUp,
+	'B': KeyArrowDown,
+	'C': KeyArrowRight,
+	'D': KeyArrowLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+}
+
+// decodeKeys converts terminal input into key events. Unless the input is final, an escape sequence or
+// character cut off at its end is returned as the rest, to be decoded again once more input arrives. In
+// final input an escape that does not start a complete sequence is the escape key.
+func decodeKeys(data []byte, final bool) (events []Event, rest []byte) {
+	for len(data) > 0 {
+		switch {
+		case data[0] == 0x1b && (len(data) == 1 || data[1] == '[' || data[1] == 'O'):
+			// skip any parameters up to the final byte of the sequence
+			end := 2
+			for end < len(data) && (data[end] < 0x40 || data[end] > 0x7e) {
+				end++
+			}
+			if end >= len(data) {
+				if !final && len(data) <= maxSequenceLen {
+					return events, data
+				}
+				events = append(events, Event{Type: EventKey, Key: KeyEsc})
+				data = data[1:]
+				continue
+			}
+			if key, ok := escapeKeys[data[end]]; ok {
+				events = append(events, Event{Type: EventKey, Key: key})
+			}
+			data = data[end+1:]
+		case data[0] == 0:
+			data = data[1:]
+		case data[0] == 0x08:
+			events = append(events, Event{Type: EventKey, Key: KeyBackspace})
+			data = data[1:]
+		case data[0] <= ' ' || data[0] == 0x7f:
+			events = append(events, Event{Type: EventKey, Key: Key(data[0])})
+			data = data[1:]
+		case !final && !utf8.FullRune(data):
+			return events, data
+		default:
+			r, size := utf8.DecodeRune(data)
+			events = append(events, Event{Type: EventKey, Key: KeyRune, Ch: r})
+			data = data[size:]
+		}
+	}
+	return events, nil
+}
+
+// sgr returns the escape sequence that sets the colors and text attributes of a cell
+func sgr(fg, bg Attribute) string {
+	seq := "\x1b[0m" + colorSeq(true, fg) + colorSeq(false, bg)
+	if fg&AttrBold != 0 {
+		seq += "\x1b[1m"
+	}
+	return seq
+}
+
+func colorSeq(foreground bool, color Attribute) string {
+	if named, ok := color.named(); ok {
+		if named == ColorDefault {
+			return ""
+		}
+		// the first eight colors are the standard colors, the next eight their bright variants
+		code := 40
+		if foreground {
+			code = 30
+		}
+		if named >= ColorDarkGray {
+			code += 60 + int(named-ColorDarkGray)
+		} else {
+			code += int(named - ColorBlack)
+		}
+		return "\x1b[" + strconv.Itoa(code) + "m"
+	}
+	prefix := "\x1b[48;"
+	if foreground {
+		prefix = "\x1b[38;"
+	}
+	if index, ok := color.palette(); ok {
+		return prefix + "5;" + strconv.Itoa(int(index)) + "m"
+	}
+	r, g, b, _ := color.rgb()
+	return prefix + "2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b)) + "m"
+}
@@ -0,0 +1,106 @@
+package terminalui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+type fakeTerminal struct {
+	io.Reader
+	bytes.Buffer
+}
+
+func (f *fakeTerminal) Read(p []byte) (int, error) { return f.Reader.Read(p) }
+
+func TestDecodeKeys(t *testing.T) {
+	key := func(k Key) Event { return Event{Type: EventKey, Key: k} }
+	ch := func(c rune) Event { return Event{Type: EventKey, Ch: c} }
+	decode := func(data string) []Event {
+		events, _ := decodeKeys([]byte(data), true)
+		return events
+	}
+
+	equal(t, []Event{key(KeyArrowUp), key(KeyArrowLeft)}, decode("\x1b[A\x1bOD"))
+	equal(t, []Event{ch('u'), key(KeySpace), ch('?'), key(KeyCtrlC)}, decode("u ?\x03"))
+	equal(t, []Event{key(KeyEsc)}, decode("\x1b"))
+	equal(t, []Event{key(KeyEsc), ch('[')}, decode("\x1b["))
+	equal(t, []Event{key(KeyBackspace), key(KeyBackspace)}, decode("\x08\x7f"))
+	// unknown sequences are skipped
+	equal(t, []Event{ch('r')}, decode("\x1b[1;5Zr"))
+	equal(t, []Event{ch('é')}, decode("é"))
+
+	// input cut off part way through a sequence or character is kept for the next read
+	events, rest := decodeKeys([]byte("w\x1b["), false)
+	equal(t, []Event{ch('w')}, events)
+	equal(t, []byte("\x1b["), rest)
+	events, rest = decodeKeys(append(rest, 'B'), false)
+	equal(t, []Event{key(KeyArrowDown)}, events)
+	equal(t, 0, len(rest))
+	events, rest = decodeKeys([]byte("é")[:1], false)
+	equal(t, 0, len(events))
+	equal(t, 1, len(rest))
+}
+
+func TestANSIScreen_splitSequence(t *testing.T) {
+	// the arrow key arrives over two reads
+	term := &fakeTerminal{Reader: io.MultiReader(strings.NewReader("\x1b["), strings.NewReader("A"))}
+	s := NewANSIScreen(term, 10, 3)
+	equal(t, nil, s.Init())
+	equal(t, Event{Type: EventKey, Key: KeyArrowUp}, s.PollEvent())
+	equal(t, EventError, s.PollEvent().Type)
+
+	// an escape followed by nothing is the escape key once the rest of a sequence could have arrived
+	r, w := io.Pipe()
+	defer w.Close()
+	s = NewANSIScreen(&fakeTerminal{Reader: r}, 10, 3)
+	equal(t, nil, s.Init())
+	defer s.Close()
+	go w.Write([]byte{0x1b})
+	equal(t, Event{Type: EventKey, Key: KeyEsc}, s.PollEvent())
+}
+
+func TestANSIScreen(t *testing.T) {
+	term := &fakeTerminal{Reader: strings.NewReader("\x1b[B")}
+	s := NewANSIScreen(term, 10, 3)
+	equal(t, nil, s.Init())
+	equal(t, Event{Type: EventKey, Key: KeyArrowDown}, s.PollEvent())
+	equal(t, EventError, s.PollEvent().Type)
+
+	term.Reset()
+	s.SetCell(1, 1, 'a', ColorRed|AttrBold, ColorBlack)
+	s.SetCell(2, 1, 'b', ColorRed|AttrBold, ColorBlack)
+	s.SetCell(20, 1, 'x', ColorDefault, ColorDefault)
+	equal(t, nil, s.Flush())
+	out := term.String()
+	equal(t, true, strings.Contains(out, "\x1b[0m\x1b[31m\x1b[40m\x1b[1mab"))
+	equal(t, false, strings.Contains(out, "x"))
+
+	// only changed cells are drawn again
+	term.Reset()
+	s.SetCell(2, 1, 'c', ColorRed|AttrBold, ColorBlack)
+	equal(t, nil, s.Flush())
+	equal(t, "\x1b[2;3H\x1b[0m\x1b[31m\x1b[40m\x1b[1mc", term.String())
+
+	// resizing keeps the cells and draws the whole screen again
+	go s.Resize(4, 2)
+	equal(t, Event{Type: EventResize, Width: 4, Height: 2}, s.PollEvent())
+	width, height := s.Size()
+	equal(t, [2]int{4, 2}, [2]int{width, height})
+	term.Reset()
+	equal(t, nil, s.Flush())
+	equal(t, true, strings.Contains(term.String(), "ac"))
+	equal(t, true, strings.HasPrefix(term.String(), "\x1b[1;1H"))
+
+	s.Close()
+	equal(t, errScreenClosed, s.Flush())
+	equal(t, EventError, s.PollEvent().Type)
+}
+
+func TestSGR(t *testing.T) {
+	equal(t, "\x1b[0m\x1b[38;5;9m\x1b[48;5;0m", sgr(PaletteColor(9), PaletteColor(0)))
+	equal(t, "\x1b[0m\x1b[38;2;1;2;3m\x1b[1m", sgr(RGBColor(1, 2, 3)|AttrBold, ColorDefault))
+	equal(t, "\x1b[0m\x1b[100m", sgr(ColorDefault, ColorDarkGray))
+	equal(t, "\x1b[0m\x1b[97m\x1b[40m", sgr(ColorLightGray, ColorBlack))
+}
@@ -0,0 +1,97 @@
+package terminalui
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+// Player chooses moves for the game when autoplay is enabled
+type Player interface {
+	// NextMove returns the Direction to shift the cells in. False is returned if no move changes the board
+	NextMove(cells game.Cells) (direction game.Direction, ok bool)
+}
+
+// autoplayer makes a move for the player at a fixed interval, asking the player in the background so the
+// board stays responsive however long it takes to choose
+type autoplayer struct {
+	player   Player
+	delay    time.Duration
+	ticker   *time.Ticker
+	moves    chan autoplayMove
+	done     chan struct{}
+	paused   bool
+	thinking bool
+}
+
+// autoplayMove is the move the player chose for the cells it was given
+type autoplayMove struct {
+	cells     game.Cells
+	direction game.Direction
+	ok        bool
+}
+
+func (a *autoplayer) start() {
+	if a.player != nil {
+		a.ticker = time.NewTicker(a.delay)
+		a.moves = make(chan autoplayMove)
+		a.done = make(chan struct{})
+	}
+}
+
+func (a *autoplayer) stop() {
+	if a.ticker != nil {
+		a.ticker.Stop()
+		close(a.done)
+	}
+}
+
+// tick returns the channel that fires when the next move is due, or nil if autoplay is off, paused or the
+// player is still choosing the last move
+func (a *autoplayer) tick() <-chan time.Time {
+	if a.ticker == nil || a.paused || a.thinking {
+		return nil
+	}
+	return a.ticker.C
+}
+
+func (a *autoplayer) togglePause() {
+	a.paused = !a.paused
+}
+
+// autoplay asks the player for the next move, unless the game is over or the board is not shown
+func (u *ui) autoplay() {
+	if !u.canAutoplay() {
+		return
+	}
+	u.autoplayer.thinking = true
+
+	var (
+		cells  = u.gc.GetCells()
+		player = u.autoplayer.player
+		moves  = u.autoplayer.moves
+		done   = u.autoplayer.done
+	)
+	go func() {
+		direction, ok := player.NextMove(cells)
+		select {
+		case moves <- autoplayMove{cells: cells, direction: direction, ok: ok}:
+		case <-done:
+		}
+	}()
+}
+
+// playMove makes the move chosen by the player, unless autoplay was paused or the board changed while it chose
+func (u *ui) playMove(move autoplayMove) {
+	u.autoplayer.thinking = false
+	if !move.ok || u.autoplayer.paused || !u.canAutoplay() || !reflect.DeepEqual(move.cells, u.gc.GetCells()) {
+		return
+	}
+	u.autoplayed = true
+	u.shiftGameController(move.direction)
+}
+
+func (u *ui) canAutoplay() bool {
+	return !u.isOver && !u.animator.running() && !u.showingStats && !u.layout.tooSmall
+}
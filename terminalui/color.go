@@ -1,6 +1,8 @@
 package terminalui
 
-import "github.com/nsf/termbox-go"
+import (
+	"github.com/brandenc40/2048/theme"
+)
 
 type OutputMode int8
 
@@ -11,89 +13,106 @@ const (
 )
 
 type colorPalate struct {
-	values      map[uint16]termbox.Attribute
-	valueText   termbox.Attribute
-	empty       termbox.Attribute
-	border      termbox.Attribute
-	score       termbox.Attribute
-	guide       termbox.Attribute
-	overlayText termbox.Attribute
-	overlayBg   termbox.Attribute
+	values      map[uint32]Attribute
+	valueText   Attribute
+	empty       Attribute
+	border      Attribute
+	score       Attribute
+	guide       Attribute
+	overlayText Attribute
+	overlayBg   Attribute
 }
 
 func normalPalate() colorPalate {
 	return colorPalate{
-		values: map[uint16]termbox.Attribute{
-			2:    termbox.ColorLightGray,
-			4:    termbox.ColorLightRed,
-			8:    termbox.ColorRed,
-			16:   termbox.ColorLightGreen,
-			32:   termbox.ColorGreen,
-			64:   termbox.ColorLightBlue,
-			128:  termbox.ColorBlue,
-			256:  termbox.ColorLightYellow,
-			512:  termbox.ColorYellow,
-			1024: termbox.ColorCyan,
-			2048: termbox.ColorMagenta,
+		values: map[uint32]Attribute{
+			2:    ColorLightGray,
+			4:    ColorLightRed,
+			8:    ColorRed,
+			16:   ColorLightGreen,
+			32:   ColorGreen,
+			64:   ColorLightBlue,
+			128:  ColorBlue,
+			256:  ColorLightYellow,
+			512:  ColorYellow,
+			1024: ColorCyan,
+			2048: ColorMagenta,
+			4096: ColorLightMagenta,
+			8192: ColorLightCyan,
 		},
-		empty:       termbox.ColorWhite,
-		border:      termbox.ColorDarkGray,
-		score:       termbox.ColorGreen | termbox.AttrBold,
-		guide:       termbox.ColorWhite | termbox.AttrBold,
-		overlayText: termbox.ColorWhite | termbox.AttrBold,
-		overlayBg:   termbox.ColorBlack,
-		valueText:   termbox.ColorBlack,
+		empty:       ColorWhite,
+		border:      ColorDarkGray,
+		score:       ColorGreen | AttrBold,
+		guide:       ColorWhite | AttrBold,
+		overlayText: ColorWhite | AttrBold,
+		overlayBg:   ColorBlack,
+		valueText:   ColorBlack,
 	}
 }
 
 func mode256Palate() colorPalate {
-	const colorMod = 1 << 32
-
 	return colorPalate{
-		values: map[uint16]termbox.Attribute{
-			2:    termbox.ColorLightGray,
-			4:    10 + colorMod,
-			8:    11 + colorMod,
-			16:   12 + colorMod,
-			32:   13 + colorMod,
-			64:   14 + colorMod,
-			128:  15 + colorMod,
-			256:  16 + colorMod,
-			512:  6 + colorMod,
-			1024: 5 + colorMod,
-			2048: 4 + colorMod,
+		values: map[uint32]Attribute{
+			2:      ColorLightGray,
+			4:      PaletteColor(9),
+			8:      PaletteColor(10),
+			16:     PaletteColor(11),
+			32:     PaletteColor(12),
+			64:     PaletteColor(13),
+			128:    PaletteColor(14),
+			256:    PaletteColor(15),
+			512:    PaletteColor(5),
+			1024:   PaletteColor(4),
+			2048:   PaletteColor(3),
+			4096:   PaletteColor(199),
+			8192:   PaletteColor(163),
+			16384:  PaletteColor(127),
+			32768:  PaletteColor(91),
+			65536:  PaletteColor(55),
+			131072: PaletteColor(19),
 		},
-		empty:       termbox.ColorWhite,
-		border:      termbox.ColorDarkGray,
-		score:       termbox.ColorGreen | termbox.AttrBold,
-		guide:       termbox.ColorWhite | termbox.AttrBold,
-		overlayText: termbox.ColorWhite | termbox.AttrBold,
-		overlayBg:   termbox.ColorBlack,
-		valueText:   termbox.ColorBlack,
+		empty:       ColorWhite,
+		border:      ColorDarkGray,
+		score:       ColorGreen | AttrBold,
+		guide:       ColorWhite | AttrBold,
+		overlayText: ColorWhite | AttrBold,
+		overlayBg:   ColorBlack,
+		valueText:   ColorBlack,
 	}
 }
 
 func modeRGBPalate() colorPalate {
+	values := make(map[uint32]Attribute, len(theme.Tiles))
+	for value, color := range theme.Tiles {
+		values[value] = rgbAttribute(color)
+	}
 	return colorPalate{
-		values: map[uint16]termbox.Attribute{
-			2:    termbox.RGBToAttribute(235, 228, 219),
-			4:    termbox.RGBToAttribute(234, 225, 204),
-			8:    termbox.RGBToAttribute(233, 180, 129),
-			16:   termbox.RGBToAttribute(232, 154, 108),
-			32:   termbox.RGBToAttribute(231, 132, 103),
-			64:   termbox.RGBToAttribute(229, 105, 72),
-			128:  termbox.RGBToAttribute(232, 209, 128),
-			256:  termbox.RGBToAttribute(232, 205, 114),
-			512:  termbox.RGBToAttribute(231, 202, 101),
-			1024: termbox.RGBToAttribute(230, 197, 90),
-			2048: termbox.RGBToAttribute(230, 196, 79),
-		},
-		valueText:   termbox.RGBToAttribute(32, 32, 31) | termbox.AttrBold,
-		empty:       termbox.RGBToAttribute(202, 193, 181),
-		border:      termbox.RGBToAttribute(185, 173, 161),
-		score:       termbox.RGBToAttribute(71, 155, 95) | termbox.AttrBold,
-		guide:       termbox.RGBToAttribute(250, 248, 240) | termbox.AttrBold,
-		overlayText: termbox.RGBToAttribute(250, 248, 240) | termbox.AttrBold,
-		overlayBg:   termbox.RGBToAttribute(32, 32, 31),
+		values:      values,
+		valueText:   rgbAttribute(theme.TileText) | AttrBold,
+		empty:       rgbAttribute(theme.Empty),
+		border:      rgbAttribute(theme.Border),
+		score:       rgbAttribute(theme.Score) | AttrBold,
+		guide:       rgbAttribute(theme.Text) | AttrBold,
+		overlayText: rgbAttribute(theme.Text) | AttrBold,
+		overlayBg:   rgbAttribute(theme.OverlayBg),
+	}
+}
+
+func rgbAttribute(c theme.RGB) Attribute {
+	return RGBColor(c.R, c.G, c.B)
+}
+
+// valueColor returns the background color for a cell value. Values beyond the largest color in the palate
+// share its color.
+func (p colorPalate) valueColor(value uint32) Attribute {
+	if color, ok := p.values[value]; ok {
+		return color
+	}
+	var largest uint32
+	for v := range p.values {
+		if v > largest && v < value {
+			largest = v
+		}
 	}
+	return p.values[largest]
 }
@@ -0,0 +1,40 @@
+package terminalui
+
+import "strconv"
+
+// size of a digit in the large font used on big tiles
+const bigDigitWidth, bigDigitHeight = 3, 3
+
+var bigDigits = [10][bigDigitHeight]string{
+	{"█▀█", "█ █", "▀▀▀"},
+	{"▄█ ", " █ ", "▄█▄"},
+	{"▀▀█", "█▀▀", "▀▀▀"},
+	{"▀▀█", " ▀█", "▀▀▀"},
+	{"█ █", "▀▀█", "  ▀"},
+	{"█▀▀", "▀▀█", "▀▀▀"},
+	{"█▀▀", "█▀█", "▀▀▀"},
+	{"▀▀█", "  █", "  ▀"},
+	{"█▀█", "█▀█", "▀▀▀"},
+	{"█▀█", "▀▀█", "▀▀▀"},
+}
+
+// drawBigValue draws the value in the large font centered within the area, leaving a column free on each
+// side. False is returned if the value does not fit.
+func (u *ui) drawBigValue(xStart, yStart, xEnd, yEnd int, value uint32, bg Attribute) bool {
+	var (
+		digits = strconv.FormatUint(uint64(value), 10)
+		width  = len(digits)*(bigDigitWidth+1) - 1
+	)
+	if width > xEnd-xStart-1 || bigDigitHeight > yEnd-yStart+1 {
+		return false
+	}
+	x := xStart + (xEnd-xStart+1-width)/2
+	y := yStart + (yEnd-yStart+1-bigDigitHeight)/2
+	for _, digit := range digits {
+		for row, line := range bigDigits[digit-'0'] {
+			u.print(x, y+row, u.colorPalate.valueText, bg, line)
+		}
+		x += bigDigitWidth + 1
+	}
+	return true
+}
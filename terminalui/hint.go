@@ -0,0 +1,153 @@
+package terminalui
+
+import (
+	"context"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+)
+
+// Hint is a move suggested to the player
+type Hint struct {
+	Direction game.Direction
+	// Rationale briefly explains the suggestion, such as the points it earns
+	Rationale string
+}
+
+// Advisor suggests moves when the player asks for a hint
+type Advisor interface {
+	// Advise returns the suggested move for the cells. It should return the best hint found so far once ctx
+	// is done. False is returned if no move changes the board.
+	Advise(ctx context.Context, cells game.Cells) (hint Hint, ok bool)
+}
+
+// hinter runs the advisor in the background so the board stays responsive while it searches
+type hinter struct {
+	advisor  Advisor
+	budget   time.Duration
+	results  chan hintResult
+	cancel   context.CancelFunc
+	id       int
+	thinking bool
+	hint     *Hint
+}
+
+type hintResult struct {
+	id   int
+	hint Hint
+	ok   bool
+}
+
+var hintArrows = map[game.Direction]struct {
+	arrow rune
+	name  string
+}{
+	game.DirectionLeft:  {'←', "left"},
+	game.DirectionUp:    {'↑', "up"},
+	game.DirectionRight: {'→', "right"},
+	game.DirectionDown:  {'↓', "down"},
+}
+
+// requestHint asks the advisor for the next move, replacing any hint already shown or in progress
+func (u *ui) requestHint() {
+	if u.hinter.advisor == nil || u.isOver {
+		return
+	}
+	u.cancelHint()
+	ctx, cancel := context.WithCancel(context.Background())
+	u.hinter.cancel = cancel
+	u.hinter.thinking = true
+
+	var (
+		id      = u.hinter.id
+		cells   = u.gc.GetCells()
+		advisor = u.hinter.advisor
+		budget  = u.hinter.budget
+		results = u.hinter.results
+	)
+	go func() {
+		searchCtx, stop := context.WithTimeout(ctx, budget)
+		defer stop()
+		hint, ok := advisor.Advise(searchCtx, cells)
+		select {
+		case results <- hintResult{id: id, hint: hint, ok: ok}:
+		case <-ctx.Done():
+		}
+	}()
+	u.drawHint()
+	u.flush()
+}
+
+// cancelHint stops any search in progress and drops the hint shown, as it no longer applies to the board
+func (u *ui) cancelHint() {
+	if u.hinter.cancel != nil {
+		u.hinter.cancel()
+		u.hinter.cancel = nil
+	}
+	u.hinter.id++
+	u.hinter.thinking = false
+	u.hinter.hint = nil
+}
+
+// showHint draws the advisor's suggestion, unless the board has changed since it was requested
+func (u *ui) showHint(res hintResult) {
+	if res.id != u.hinter.id {
+		return
+	}
+	u.hinter.cancel()
+	u.hinter.cancel = nil
+	u.hinter.thinking = false
+	if res.ok {
+		u.hinter.hint = &res.hint
+	}
+	if u.showingStats || u.animator.running() {
+		return
+	}
+	u.drawGameBoard()
+}
+
+// drawHint draws an arrow on the side of the board the hint points to and explains it below the score
+func (u *ui) drawHint() {
+	if u.layout.tooSmall {
+		return
+	}
+	y := u.layout.scoreY + 1
+	u.clearInfoLine(y)
+	var msg string
+	switch {
+	case u.hinter.thinking:
+		msg = "Hint: thinking..."
+	case u.hinter.hint != nil:
+		hint := hintArrows[u.hinter.hint.Direction]
+		msg = "Hint: " + string(hint.arrow) + " " + hint.name
+		if u.hinter.hint.Rationale != "" {
+			msg += ", " + u.hinter.hint.Rationale
+		}
+		u.drawHintArrow(u.hinter.hint.Direction, hint.arrow)
+	default:
+		return
+	}
+	if maxLen := u.layout.infoXEnd - u.layout.scoreX; len([]rune(msg)) > maxLen {
+		msg = string([]rune(msg)[:maxLen])
+	}
+	u.print(u.layout.scoreX, y, u.colorPalate.guide, ColorDefault, msg)
+}
+
+func (u *ui) drawHintArrow(direction game.Direction, arrow rune) {
+	var (
+		midX = u.layout.borderXStart + u.layout.width/2
+		midY = u.layout.borderYStart + u.layout.height/2
+		fg   = u.colorPalate.overlayText
+		bg   = u.colorPalate.overlayBg
+	)
+	switch direction {
+	case game.DirectionLeft:
+		u.screen.SetCell(u.layout.borderXStart, midY, arrow, fg, bg)
+	case game.DirectionRight:
+		u.screen.SetCell(u.layout.borderXEnd, midY, arrow, fg, bg)
+	case game.DirectionUp:
+		u.screen.SetCell(midX, u.layout.borderYStart, arrow, fg, bg)
+	case game.DirectionDown:
+		u.screen.SetCell(midX, u.layout.borderYEnd, arrow, fg, bg)
+	}
+}
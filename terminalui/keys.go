@@ -0,0 +1,225 @@
+package terminalui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Action is something the player can do with a key press
+type Action string
+
+const (
+	ActionUp       Action = "up"
+	ActionDown     Action = "down"
+	ActionLeft     Action = "left"
+	ActionRight    Action = "right"
+	ActionReset    Action = "reset"
+	ActionUndo     Action = "undo"
+	ActionRedo     Action = "redo"
+	ActionContinue Action = "continue"
+	ActionStats    Action = "stats"
+	ActionPause    Action = "pause"
+	ActionHint     Action = "hint"
+	ActionQuit     Action = "quit"
+)
+
+// actions lists every action in the order they are shown in the guide
+var actions = [...]Action{
+	ActionUp, ActionDown, ActionLeft, ActionRight, ActionReset, ActionUndo, ActionRedo, ActionContinue,
+	ActionStats, ActionPause, ActionHint, ActionQuit,
+}
+
+var actionNames = map[Action]string{
+	ActionUp:       "Move up",
+	ActionDown:     "Move down",
+	ActionLeft:     "Move left",
+	ActionRight:    "Move right",
+	ActionReset:    "Reset",
+	ActionUndo:     "Undo",
+	ActionRedo:     "Redo",
+	ActionContinue: "Keep going",
+	ActionStats:    "Statistics",
+	ActionPause:    "Pause",
+	ActionHint:     "Hint",
+	ActionQuit:     "Quit",
+}
+
+// KeyMap binds each action to the keys that perform it. A key is named by the character it types, such as
+// "w" or "?", or for keys that do not type a character by one of: up, down, left, right, home, end, esc,
+// enter, tab, space, backspace and ctrl+c. Characters are case sensitive.
+type KeyMap map[Action][]string
+
+// DefaultKeyMap returns the default bindings, which move with the arrow keys, WASD and the vim keys hjkl
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		ActionUp:       {"up", "w", "k"},
+		ActionDown:     {"down", "s", "j"},
+		ActionLeft:     {"left", "a", "h"},
+		ActionRight:    {"right", "d", "l"},
+		ActionReset:    {"r", "R"},
+		ActionUndo:     {"u", "U"},
+		ActionRedo:     {"y", "Y"},
+		ActionContinue: {"c", "C"},
+		ActionStats:    {"t", "T"},
+		ActionPause:    {"p", "P"},
+		ActionHint:     {"?"},
+		ActionQuit:     {"esc", "ctrl+c"},
+	}
+}
+
+// LoadKeyMap reads key bindings from a JSON file mapping actions to lists of keys, such as
+// {"hint": ["?", "i"]}. Actions missing from the file keep their default keys. The default bindings are
+// returned if the file does not exist.
+func LoadKeyMap(path string) (KeyMap, error) {
+	keys := DefaultKeyMap()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return keys, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading key bindings: %w", err)
+	}
+	var file KeyMap
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decoding key bindings: %w", err)
+	}
+	for action, bound := range file {
+		keys[action] = bound
+	}
+	if err := keys.Validate(); err != nil {
+		return nil, fmt.Errorf("key bindings in %s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// Validate returns an error if an action or key is unknown, a key is bound to more than one action or no key
+// quits the game
+func (m KeyMap) Validate() error {
+	_, err := m.bindings()
+	return err
+}
+
+// keyPress identifies a key by the Key and character of its events
+type keyPress struct {
+	key Key
+	ch  rune
+}
+
+func eventKeyPress(ev Event) keyPress {
+	if ev.Key != KeyRune {
+		return keyPress{key: ev.Key}
+	}
+	return keyPress{key: KeyRune, ch: ev.Ch}
+}
+
+var namedKeys = map[string]keyPress{
+	"up":        {key: KeyArrowUp},
+	"down":      {key: KeyArrowDown},
+	"left":      {key: KeyArrowLeft},
+	"right":     {key: KeyArrowRight},
+	"home":      {key: KeyHome},
+	"end":       {key: KeyEnd},
+	"esc":       {key: KeyEsc},
+	"enter":     {key: KeyEnter},
+	"tab":       {key: KeyTab},
+	"space":     {key: KeySpace},
+	"backspace": {key: KeyBackspace},
+	"ctrl+c":    {key: KeyCtrlC},
+}
+
+// arrowLabels are shown in the guide for the arrow keys, other named keys are shown in upper case
+var arrowLabels = map[string]string{
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+}
+
+func parseKey(name string) (keyPress, error) {
+	if key, ok := namedKeys[name]; ok {
+		return key, nil
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	if size == 0 || size != len(name) || r == utf8.RuneError || !unicode.IsGraphic(r) {
+		return keyPress{}, fmt.Errorf("unknown key %q", name)
+	}
+	if r == ' ' {
+		return namedKeys["space"], nil
+	}
+	return keyPress{key: KeyRune, ch: r}, nil
+}
+
+// bindings maps every key press to the action bound to it
+func (m KeyMap) bindings() (map[keyPress]Action, error) {
+	var unknown []string
+	for action := range m {
+		if _, ok := actionNames[action]; !ok {
+			unknown = append(unknown, string(action))
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown action %q", unknown[0])
+	}
+	var (
+		bindings = make(map[keyPress]Action)
+		names    = make(map[keyPress]string)
+	)
+	for _, action := range actions {
+		for _, name := range m[action] {
+			key, err := parseKey(name)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", action, err)
+			}
+			if other, ok := bindings[key]; ok && other != action {
+				return nil, fmt.Errorf("key %q is bound to both %s and %s", names[key], other, action)
+			}
+			bindings[key], names[key] = action, name
+		}
+	}
+	if len(m[ActionQuit]) == 0 {
+		return nil, errors.New("no key is bound to quit")
+	}
+	return bindings, nil
+}
+
+// describe returns a guide line listing the keys bound to the action
+func (m KeyMap) describe(action Action) string {
+	return describeKeys(actionNames[action], m[action])
+}
+
+// describeKeys returns a guide line naming something the player can do and listing its keys, showing a
+// letter bound in both cases once in lower case
+func describeKeys(name string, keys []string) string {
+	var (
+		bound  = make(map[string]bool, len(keys))
+		labels = make([]string, 0, len(keys))
+	)
+	for _, name := range keys {
+		bound[name] = true
+	}
+	for _, name := range keys {
+		if lower := strings.ToLower(name); lower != name && bound[lower] {
+			continue
+		}
+		labels = append(labels, keyLabel(name))
+	}
+	return fmt.Sprintf("%-12s%s", name, strings.Join(labels, "  "))
+}
+
+// keyLabel returns how a key is shown to the player
+func keyLabel(name string) string {
+	if label, ok := arrowLabels[name]; ok {
+		return label
+	}
+	if _, ok := namedKeys[name]; ok {
+		return strings.ToUpper(name)
+	}
+	return name
+}
@@ -0,0 +1,108 @@
+package terminalui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brandenc40/2048/game"
+)
+
+func TestKeyMap_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		keys KeyMap
+		err  string
+	}{
+		{"default", DefaultKeyMap(), ""},
+		{"unknown action", KeyMap{ActionQuit: {"q"}, "jump": {"x"}}, `unknown action "jump"`},
+		{"unknown key", KeyMap{ActionQuit: {"f1"}}, `quit: unknown key "f1"`},
+		{"conflict", KeyMap{ActionLeft: {"h"}, ActionHint: {"h"}, ActionQuit: {"q"}}, `key "h" is bound to both left and hint`},
+		{"same key twice", KeyMap{ActionQuit: {"q", "q"}}, ""},
+		{"no quit", KeyMap{ActionUp: {"w"}}, "no key is bound to quit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.keys.Validate()
+			if tt.err == "" {
+				equal(t, nil, err)
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected error %q", tt.err)
+			}
+			equal(t, tt.err, err.Error())
+		})
+	}
+}
+
+func TestLoadKeyMap(t *testing.T) {
+	dir := t.TempDir()
+
+	keys, err := LoadKeyMap(filepath.Join(dir, "missing.json"))
+	equal(t, nil, err)
+	equal(t, DefaultKeyMap(), keys)
+
+	path := filepath.Join(dir, "keys.json")
+	if err := os.WriteFile(path, []byte(`{"hint": ["?", "i"], "quit": ["q", "esc"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err = LoadKeyMap(path)
+	equal(t, nil, err)
+	equal(t, []string{"?", "i"}, keys[ActionHint])
+	equal(t, []string{"q", "esc"}, keys[ActionQuit])
+	equal(t, DefaultKeyMap()[ActionUp], keys[ActionUp])
+
+	if err := os.WriteFile(path, []byte(`{"hint": ["w"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadKeyMap(path)
+	equal(t, true, err != nil && strings.Contains(err.Error(), `key "w" is bound to both up and hint`))
+}
+
+func TestKeyMap_describe(t *testing.T) {
+	keys := DefaultKeyMap()
+	equal(t, "Move up     ↑  w  k", keys.describe(ActionUp))
+	equal(t, "Reset       r", keys.describe(ActionReset))
+	equal(t, "Quit        ESC  CTRL+C", keys.describe(ActionQuit))
+	keys[ActionReset] = []string{"R", "space"}
+	equal(t, "Reset       R  SPACE", keys.describe(ActionReset))
+}
+
+func TestUI_keyMap(t *testing.T) {
+	var (
+		gc        = game.NewController(game.WithSeed(3))
+		reference = game.NewController(game.WithSeed(3))
+		keys      = DefaultKeyMap()
+	)
+	keys[ActionReset] = []string{"n"}
+	keys[ActionQuit] = []string{"q"}
+	u, screen := newTestUI(t, gc, WithKeyMap(keys), WithoutAnimations())
+	u.drawGameBoard()
+
+	press := func(ch rune) bool {
+		return u.handleEvent(Event{Type: EventKey, Key: KeyRune, Ch: ch})
+	}
+	equal(t, false, press('a'))
+	equal(t, false, press('k'))
+	reference.Shift(game.DirectionLeft)
+	reference.Shift(game.DirectionUp)
+	equal(t, reference.GetCells(), gc.GetCells())
+
+	// keys that are no longer bound do nothing
+	equal(t, false, press('r'))
+	equal(t, false, u.handleEvent(Event{Type: EventKey, Key: KeyEsc}))
+	equal(t, reference.GetCells(), gc.GetCells())
+	equal(t, true, strings.Contains(screen.Text(), "Reset       n"))
+	equal(t, true, press('q'))
+}
+
+func TestWithKeyMap_conflict(t *testing.T) {
+	defer func() {
+		equal(t, "WithKeyMap: key \"h\" is bound to both left and hint", recover())
+	}()
+	keys := DefaultKeyMap()
+	keys[ActionHint] = []string{"h"}
+	WithKeyMap(keys).apply(newUI(game.NewController()))
+}
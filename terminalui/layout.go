@@ -0,0 +1,192 @@
+package terminalui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	// top left corner of the board
+	x, y = 2, 1
+	// infoWidth is the width of the score and hint lines below the board, which may be wider than a small board
+	infoWidth = 58
+)
+
+// tileSize is the size of the tiles and the gaps between them, which also pad the tiles from the border
+type tileSize struct {
+	width, height int
+	xGap, yGap    int
+}
+
+// tileSizes are tried from largest to smallest, the largest that fits on the screen is used
+var tileSizes = [...]tileSize{
+	{width: 22, height: 7, xGap: 3, yGap: 1},
+	{width: 17, height: 5, xGap: 2, yGap: 1},
+	{width: 13, height: 4, xGap: 2, yGap: 1},
+	{width: 9, height: 3, xGap: 1, yGap: 1},
+	{width: 6, height: 3, xGap: 1, yGap: 1},
+	{width: 6, height: 1, xGap: 1, yGap: 1},
+}
+
+// guidePlacement is where the guide is drawn in relation to the board
+type guidePlacement uint8
+
+const (
+	guideHidden guidePlacement = iota
+	guideBeside
+	guideBelow
+)
+
+// layout holds the screen positions of the game elements, sized for the board dimensions and the screen
+type layout struct {
+	screenWidth  int
+	screenHeight int
+	// tooSmall is set when even the smallest board does not fit on the screen, minWidth and minHeight are
+	// the size it needs
+	tooSmall     bool
+	minWidth     int
+	minHeight    int
+	tile         tileSize
+	bigDigits    bool
+	width        int
+	height       int
+	borderXStart int
+	borderXEnd   int
+	borderYStart int
+	borderYEnd   int
+	cellsXStart  int
+	cellsYStart  int
+	infoXEnd     int
+	scoreX       int
+	scoreY       int
+	guide        guidePlacement
+	showLogo     bool
+	guideX       int
+	guideY       int
+}
+
+// newLayout fits the board and the guide text on the screen. The largest tiles that leave room for the
+// guide beside or below the board are used, dropping the logo and then the guide if nothing fits.
+func newLayout(rows, cols, screenWidth, screenHeight int, guide []string) layout {
+	for _, showLogo := range [...]bool{true, false} {
+		lines := guide
+		if showLogo {
+			lines = withLogo(guide)
+		}
+		for _, size := range tileSizes {
+			l := boardLayout(rows, cols, screenWidth, screenHeight, size)
+			if l.fits() && l.placeGuide(lines) {
+				l.showLogo = showLogo
+				return l
+			}
+		}
+	}
+	for _, size := range tileSizes {
+		if l := boardLayout(rows, cols, screenWidth, screenHeight, size); l.fits() {
+			return l
+		}
+	}
+	l := boardLayout(rows, cols, screenWidth, screenHeight, tileSizes[len(tileSizes)-1])
+	l.tooSmall = true
+	l.minWidth = l.borderXEnd + 1
+	l.minHeight = l.scoreY + 2
+	return l
+}
+
+// boardLayout positions the board with the tile size, without checking that it fits on the screen
+func boardLayout(rows, cols, screenWidth, screenHeight int, size tileSize) layout {
+	l := layout{
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+		tile:         size,
+		bigDigits:    size.height >= bigDigitHeight+2,
+		borderXStart: x,
+		borderYStart: y,
+	}
+	l.cellsXStart = l.borderXStart + size.xGap
+	l.cellsYStart = l.borderYStart + size.yGap
+	// the border is as thick as the gaps between the tiles
+	l.borderXEnd = l.cellsXStart + cols*(size.width+size.xGap) - 1
+	l.borderYEnd = l.cellsYStart + rows*(size.height+size.yGap) - 1
+	l.width = l.borderXEnd - l.borderXStart
+	l.height = l.borderYEnd - l.borderYStart
+	l.infoXEnd = l.borderXEnd
+	if end := l.borderXStart + infoWidth - 1; end > l.infoXEnd {
+		l.infoXEnd = end
+	}
+	l.scoreX = l.borderXStart + 2
+	l.scoreY = l.borderYEnd + 2
+	return l
+}
+
+// fits returns true if the board and the score and hint lines below it fit on the screen
+func (l *layout) fits() bool {
+	return l.borderXEnd < l.screenWidth && l.scoreY+1 < l.screenHeight
+}
+
+// placeGuide puts the guide beside the board, or below the score and hint lines when the screen is too
+// narrow, returning false if it fits in neither place
+func (l *layout) placeGuide(lines []string) bool {
+	width := 0
+	for _, line := range lines {
+		if w := runewidth.StringWidth(strings.TrimRight(line, " ")); w > width {
+			width = w
+		}
+	}
+	l.guideX, l.guideY = l.borderXEnd+2, l.borderYStart+1
+	if l.guideY+len(lines) > l.scoreY {
+		// keep clear of the score and hint lines
+		l.guideX = l.infoXEnd + 2
+	}
+	if l.guideX+width <= l.screenWidth && l.guideY+len(lines) <= l.screenHeight {
+		l.guide = guideBeside
+		return true
+	}
+	l.guideX, l.guideY = l.borderXStart, l.scoreY+3
+	if l.guideX+width <= l.screenWidth && l.guideY+len(lines) <= l.screenHeight {
+		l.guide = guideBelow
+		return true
+	}
+	return false
+}
+
+// withLogo returns the guide text with the logo above it
+func withLogo(guide []string) []string {
+	return append(logo[:len(logo):len(logo)], guide...)
+}
+
+// relayout sizes the game for a screen of the given size
+func (u *ui) relayout(width, height int) {
+	cells := u.gc.GetCells()
+	u.layout = newLayout(cells.Rows(), cells.Cols(), width, height, u.textMsg())
+}
+
+// drawTooSmall asks for a larger terminal in place of a board that does not fit on the screen
+func (u *ui) drawTooSmall() {
+	u.screen.Clear()
+	lines := []string{
+		"Terminal too small",
+		"Resize to at least " + strconv.Itoa(u.layout.minWidth) + "x" + strconv.Itoa(u.layout.minHeight),
+	}
+	y := u.layout.screenHeight/2 - 1
+	if y < 0 {
+		y = 0
+	}
+	for i, line := range lines {
+		x := (u.layout.screenWidth - len(line)) / 2
+		if x < 0 {
+			x = 0
+		}
+		u.print(x, y+i, u.colorPalate.guide, ColorDefault, line)
+	}
+	u.flush()
+}
+
+// clearInfoLine clears one of the lines below the board before it is drawn again
+func (u *ui) clearInfoLine(y int) {
+	for x := u.layout.borderXStart; x <= u.layout.infoXEnd; x++ {
+		u.screen.SetCell(x, y, ' ', ColorDefault, ColorDefault)
+	}
+}
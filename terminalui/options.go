@@ -1,6 +1,10 @@
 package terminalui
 
-import "github.com/nsf/termbox-go"
+import (
+	"time"
+
+	"github.com/brandenc40/2048/stats"
+)
 
 type Option interface {
 	apply(ui *ui)
@@ -17,15 +21,127 @@ type outputModeOption struct {
 func (o outputModeOption) apply(ui *ui) {
 	switch o.mode {
 	case OutputMode256:
-		termbox.SetOutputMode(termbox.Output256)
 		ui.colorPalate = mode256Palate()
 	case OutputModeRGB:
-		termbox.SetOutputMode(termbox.OutputRGB)
 		ui.colorPalate = modeRGBPalate()
 	case OutputModeNormal:
-		termbox.SetOutputMode(termbox.OutputNormal)
 		ui.colorPalate = normalPalate()
 	default:
 		panic("WithOutputMode: invalid output mode")
 	}
+	ui.outputMode = o.mode
+}
+
+// WithScreen draws the game to the screen instead of the terminal the process runs in, such as a terminal
+// connected over the network
+func WithScreen(screen Screen) Option {
+	return screenOption{screen: screen}
+}
+
+type screenOption struct {
+	screen Screen
+}
+
+func (o screenOption) apply(ui *ui) {
+	if o.screen == nil {
+		panic("WithScreen: screen must not be nil")
+	}
+	ui.screen = o.screen
+}
+
+// WithAnimationDuration sets how long tiles take to slide, merge and spawn after each move. A duration of
+// zero or less disables animations.
+func WithAnimationDuration(duration time.Duration) Option {
+	return animationOption{duration: duration}
+}
+
+// WithoutAnimations draws each move instantly
+func WithoutAnimations() Option {
+	return animationOption{}
+}
+
+type animationOption struct {
+	duration time.Duration
+}
+
+func (o animationOption) apply(ui *ui) {
+	ui.animator = newAnimator(o.duration)
+}
+
+// WithEndless lets the player choose to keep playing after reaching the win target instead of ending the game
+func WithEndless() Option {
+	return endlessOption{}
+}
+
+type endlessOption struct{}
+
+func (o endlessOption) apply(ui *ui) {
+	ui.endless = true
+}
+
+// WithStats records every finished game in the store and shows the player's statistics. Games that autoplay
+// moved in are not recorded.
+func WithStats(store *stats.Store) Option {
+	return statsOption{store: store}
+}
+
+type statsOption struct {
+	store *stats.Store
+}
+
+func (o statsOption) apply(ui *ui) {
+	ui.stats = o.store
+}
+
+// WithAutoplay lets the player make a move every delay. The game can still be played with the keyboard and
+// autoplay can be paused at any time.
+func WithAutoplay(player Player, delay time.Duration) Option {
+	return autoplayOption{player: player, delay: delay}
+}
+
+type autoplayOption struct {
+	player Player
+	delay  time.Duration
+}
+
+func (o autoplayOption) apply(ui *ui) {
+	if o.player == nil || o.delay <= 0 {
+		panic("WithAutoplay: player must not be nil and delay must be positive")
+	}
+	ui.autoplayer = autoplayer{player: o.player, delay: o.delay}
+}
+
+// WithAdvisor lets the player ask the advisor for a hint. The advisor searches in the background for at most
+// budget, and the hint is dropped if the board changes before it is ready.
+func WithAdvisor(advisor Advisor, budget time.Duration) Option {
+	return advisorOption{advisor: advisor, budget: budget}
+}
+
+type advisorOption struct {
+	advisor Advisor
+	budget  time.Duration
+}
+
+func (o advisorOption) apply(ui *ui) {
+	if o.advisor == nil || o.budget <= 0 {
+		panic("WithAdvisor: advisor must not be nil and budget must be positive")
+	}
+	ui.hinter = hinter{advisor: o.advisor, budget: o.budget, results: make(chan hintResult)}
+}
+
+// WithKeyMap replaces the default key bindings. The guide shown beside the board lists the keys bound.
+func WithKeyMap(keys KeyMap) Option {
+	return keyMapOption{keys: keys}
+}
+
+type keyMapOption struct {
+	keys KeyMap
+}
+
+func (o keyMapOption) apply(ui *ui) {
+	bindings, err := o.keys.bindings()
+	if err != nil {
+		panic("WithKeyMap: " + err.Error())
+	}
+	ui.keyMap, ui.keys = o.keys, bindings
 }
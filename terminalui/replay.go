@@ -0,0 +1,319 @@
+package terminalui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/brandenc40/2048/replay"
+)
+
+const (
+	defaultReplaySpeed = 2 // index of 1x in replaySpeeds
+	// maxReplayDelay caps the pause between replayed actions, before the speed is applied, so long breaks
+	// taken by the player are skipped
+	maxReplayDelay = 2 * time.Second
+)
+
+var replaySpeeds = [...]float64{0.25, 0.5, 1, 2, 4, 8, 16}
+
+// replayControl is something the viewer can do while watching a replay. It is performed by the keys bound to
+// its action in the KeyMap, and by its fixed keys unless the KeyMap binds them to another replay control.
+type replayControl struct {
+	name   string
+	action Action
+	fixed  []string
+}
+
+const (
+	replayToggle = iota
+	replayBack
+	replayAhead
+	replayFaster
+	replaySlower
+	replayRestart
+	replayQuit
+)
+
+// replayControls are listed in the order they are shown in the guide
+var replayControls = [...]replayControl{
+	replayToggle:  {name: "Play/pause", action: ActionPause, fixed: []string{"space"}},
+	replayBack:    {name: "Step back", action: ActionLeft},
+	replayAhead:   {name: "Step ahead", action: ActionRight},
+	replayFaster:  {name: "Faster", fixed: []string{"+", "="}},
+	replaySlower:  {name: "Slower", fixed: []string{"-", "_"}},
+	replayRestart: {name: "Restart", action: ActionReset},
+	replayQuit:    {name: "Quit", action: ActionQuit},
+}
+
+// replayControlOf returns the replay control the key press performs, false if it performs none
+func (u *ui) replayControlOf(key keyPress) (int, bool) {
+	if action, ok := u.keys[key]; ok {
+		for i, c := range replayControls {
+			if c.action != "" && c.action == action {
+				return i, true
+			}
+		}
+	}
+	for i, c := range replayControls {
+		for _, name := range c.fixed {
+			if fixed, _ := parseKey(name); fixed == key {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// replayMsg is the guide shown while watching a replay, listing the keys of every replay control
+func (u *ui) replayMsg() []string {
+	msg := []string{
+		"REPLAY: Watch a recorded game play out move",
+		"by move at the pace it was played.",
+		"",
+	}
+	for i, c := range replayControls {
+		keys := append([]string(nil), u.keyMap[c.action]...)
+		for _, name := range c.fixed {
+			// skipped when the KeyMap binds it to the control's action, as it is listed already
+			if key, _ := parseKey(name); u.performs(key, i) && (c.action == "" || u.keys[key] != c.action) {
+				keys = append(keys, name)
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		if i == replayQuit {
+			msg = append(msg, "")
+		}
+		msg = append(msg, describeKeys(c.name, keys))
+	}
+	return msg
+}
+
+func (u *ui) performs(key keyPress, control int) bool {
+	i, ok := u.replayControlOf(key)
+	return ok && i == control
+}
+
+// replayer plays back a replay on a timer
+type replayer struct {
+	player *replay.Player
+	speed  int
+	paused bool
+	timer  *time.Timer
+	err    error
+}
+
+// RunReplay plays back a recorded game in the terminal, returning when the viewer quits. An error is
+// returned if the screen fails.
+func RunReplay(player *replay.Player, options ...Option) error {
+	u := newUI(player.Controller())
+	u.replayer = &replayer{player: player, speed: defaultReplaySpeed}
+	if err := u.initialize(options...); err != nil {
+		return err
+	}
+	defer u.screen.Close()
+
+	u.drawReplayFrame()
+	u.runReplayLoop()
+	return u.err
+}
+
+// schedule starts the timer for the next action, unless the replay has ended
+func (r *replayer) schedule() {
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	delay, ok := r.player.NextDelay()
+	if !ok || r.err != nil {
+		return
+	}
+	if delay > maxReplayDelay {
+		delay = maxReplayDelay
+	}
+	r.timer = time.NewTimer(time.Duration(float64(delay) / replaySpeeds[r.speed]))
+}
+
+func (r *replayer) stop() {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+}
+
+// tick returns the channel that fires when the next action is due, or nil if the replay is paused or has ended
+func (r *replayer) tick() <-chan time.Time {
+	if r.timer == nil || r.paused {
+		return nil
+	}
+	return r.timer.C
+}
+
+func (u *ui) runReplayLoop() {
+	done := make(chan struct{})
+	defer close(done)
+	events := u.pollEvents(done)
+	u.replayer.schedule()
+	defer u.replayer.stop()
+	for u.err == nil {
+		select {
+		case <-u.replayer.tick():
+			u.stepReplay()
+		case <-u.animator.tick():
+			if u.animator.advance() {
+				u.drawAnimationFrame()
+			} else {
+				u.drawReplayFrame()
+			}
+		case ev := <-events:
+			if quit := u.handleReplayEvent(ev); quit {
+				return
+			}
+		}
+	}
+}
+
+// handleReplayEvent responds to a terminal event while watching a replay, returning true if the viewer quits
+func (u *ui) handleReplayEvent(ev Event) (quit bool) {
+	switch ev.Type {
+	case EventKey:
+		if u.animator.running() {
+			u.animator.stop()
+			u.drawReplayFrame()
+		}
+		control, ok := u.replayControlOf(eventKeyPress(ev))
+		if !ok {
+			return false
+		}
+		switch control {
+		case replayToggle:
+			u.toggleReplay()
+		case replayBack:
+			u.replayer.paused = true
+			u.stepReplayBack()
+		case replayAhead:
+			u.replayer.paused = true
+			u.stepReplay()
+		case replayFaster:
+			u.changeReplaySpeed(1)
+		case replaySlower:
+			u.changeReplaySpeed(-1)
+		case replayRestart:
+			u.seekReplay(0)
+		case replayQuit:
+			return true
+		}
+	case EventResize:
+		if u.animator.running() {
+			u.animator.stop()
+		}
+		u.relayout(ev.Width, ev.Height)
+		u.screen.Clear()
+		u.drawReplayFrame()
+	case EventError:
+		u.fail(ev.Err)
+	}
+	return false
+}
+
+// stepReplay plays the next action, animating it if it shifted the board
+func (u *ui) stepReplay() {
+	res, err := u.replayer.player.Step()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			u.replayer.err = err
+		}
+		u.drawReplayFrame()
+		return
+	}
+	u.gc = u.replayer.player.Controller()
+	u.replayer.schedule()
+	if res.Changed && u.animator.enabled() {
+		u.animator.start(res)
+		u.drawAnimationFrame()
+		return
+	}
+	u.drawReplayFrame()
+}
+
+func (u *ui) stepReplayBack() {
+	if u.replayer.player.Back() {
+		u.gc = u.replayer.player.Controller()
+		u.replayer.err = nil
+		u.replayer.schedule()
+	}
+	u.drawReplayFrame()
+}
+
+func (u *ui) seekReplay(pos int) {
+	if err := u.replayer.player.Seek(pos); err != nil {
+		u.replayer.err = err
+	} else {
+		u.replayer.err = nil
+	}
+	u.gc = u.replayer.player.Controller()
+	u.replayer.schedule()
+	u.drawReplayFrame()
+}
+
+// toggleReplay pauses or resumes playback, starting over if the replay has ended
+func (u *ui) toggleReplay() {
+	if u.replayer.player.Done() {
+		u.replayer.paused = false
+		u.seekReplay(0)
+		return
+	}
+	u.replayer.paused = !u.replayer.paused
+	if !u.replayer.paused {
+		u.replayer.schedule()
+	}
+	u.drawReplayFrame()
+}
+
+func (u *ui) changeReplaySpeed(delta int) {
+	speed := u.replayer.speed + delta
+	if speed < 0 || speed >= len(replaySpeeds) {
+		return
+	}
+	u.replayer.speed = speed
+	u.replayer.schedule()
+	u.drawReplayFrame()
+}
+
+func (u *ui) drawReplayFrame() {
+	if u.layout.tooSmall {
+		u.drawTooSmall()
+		return
+	}
+	u.drawGameBackground()
+	u.drawGameCells()
+	u.drawScore()
+	u.drawGuide()
+	u.drawReplayStatus()
+	if u.gc.Lost() {
+		u.drawOverlayMessage("NO MORE MOVES")
+	}
+	u.flush()
+}
+
+// drawReplayStatus draws the replay position, speed and state below the score
+func (u *ui) drawReplayStatus() {
+	y := u.layout.scoreY + 1
+	u.clearInfoLine(y)
+	state := "Playing"
+	switch {
+	case u.replayer.err != nil:
+		state = "Stopped, the replay does not match this game"
+	case u.replayer.player.Done():
+		state = "Finished"
+	case u.replayer.paused:
+		state = "Paused"
+	}
+	msg := fmt.Sprintf("Move %d/%d   Speed %sx   %s",
+		u.replayer.player.Position(), u.replayer.player.Len(),
+		strconv.FormatFloat(replaySpeeds[u.replayer.speed], 'g', -1, 64), state)
+	u.print(u.layout.scoreX, y, u.colorPalate.guide, ColorDefault, msg)
+}
@@ -0,0 +1,67 @@
+package terminalui
+
+import (
+	"testing"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/replay"
+)
+
+func newTestReplay(t *testing.T, options ...Option) *ui {
+	t.Helper()
+	rec := replay.NewRecorder(game.NewController(game.WithSeed(3)), nil)
+	rec.Shift(game.DirectionLeft)
+	rec.Shift(game.DirectionUp)
+	r, _ := rec.Replay()
+	u := newUI(nil)
+	u.replayer = &replayer{player: replay.NewPlayer(r), speed: defaultReplaySpeed}
+	u.gc = u.replayer.player.Controller()
+	if err := u.initialize(append([]Option{WithScreen(NewSimulationScreen(120, 40))}, options...)...); err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestUI_replayMsg(t *testing.T) {
+	msg := newTestReplay(t).replayMsg()
+	equal(t, []string{
+		"Play/pause  p  SPACE",
+		"Step back   ←  a  h",
+		"Step ahead  →  d  l",
+		"Faster      +  =",
+		"Slower      -  _",
+		"Restart     r",
+		"",
+		"Quit        ESC  CTRL+C",
+	}, msg[3:])
+
+	// fixed keys bound to another replay control by the key map are left out
+	keys := DefaultKeyMap()
+	keys[ActionPause] = []string{"space"}
+	keys[ActionReset] = []string{"+"}
+	keys[ActionQuit] = []string{"q"}
+	msg = newTestReplay(t, WithKeyMap(keys)).replayMsg()
+	equal(t, "Play/pause  SPACE", msg[3])
+	equal(t, "Faster      =", msg[6])
+	equal(t, "Restart     +", msg[8])
+	equal(t, "Quit        q", msg[10])
+}
+
+func TestUI_handleReplayEvent(t *testing.T) {
+	keys := DefaultKeyMap()
+	keys[ActionRight] = []string{"n"}
+	keys[ActionQuit] = []string{"q"}
+	u := newTestReplay(t, WithKeyMap(keys), WithoutAnimations())
+
+	// the arrow key is no longer bound, so only the key map's key steps ahead
+	equal(t, false, u.handleReplayEvent(Event{Type: EventKey, Key: KeyArrowRight}))
+	equal(t, 0, u.replayer.player.Position())
+	equal(t, false, u.handleReplayEvent(Event{Type: EventKey, Key: KeyRune, Ch: 'n'}))
+	equal(t, 1, u.replayer.player.Position())
+	equal(t, true, u.replayer.paused)
+	equal(t, false, u.handleReplayEvent(Event{Type: EventKey, Key: KeySpace}))
+	equal(t, false, u.replayer.paused)
+
+	equal(t, false, u.handleReplayEvent(Event{Type: EventKey, Key: KeyEsc}))
+	equal(t, true, u.handleReplayEvent(Event{Type: EventKey, Key: KeyRune, Ch: 'q'}))
+}
@@ -0,0 +1,199 @@
+package terminalui
+
+// Screen is a terminal the game is drawn to and read from. Cells drawn with SetCell are kept until they are
+// drawn over or the screen is cleared, and only appear once the screen is flushed.
+//
+// The terminal the process runs in is drawn with termbox by default, NewTcellScreen draws it with tcell
+// instead. NewANSIScreen draws to a terminal connected over a stream and NewSimulationScreen keeps the
+// screen in memory.
+type Screen interface {
+	// Init prepares the terminal for drawing, Close restores it once the game ends
+	Init() error
+	Close()
+	// SetOutputMode tells the screen which colors the game draws with
+	SetOutputMode(mode OutputMode)
+	Size() (width, height int)
+	Clear()
+	SetCell(x, y int, ch rune, fg, bg Attribute)
+	Flush() error
+	// PollEvent waits for the next key press or resize. An event of type EventError is returned if input can
+	// no longer be read, such as after the screen is closed.
+	PollEvent() Event
+}
+
+// Attribute is the color of a cell's text or background. The text color may be combined with AttrBold.
+type Attribute uint64
+
+// The named colors are the 16 colors every color terminal supports, their exact shade depends on the
+// terminal's theme
+const (
+	ColorDefault Attribute = iota
+	ColorBlack
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+	ColorDarkGray
+	ColorLightRed
+	ColorLightGreen
+	ColorLightYellow
+	ColorLightBlue
+	ColorLightMagenta
+	ColorLightCyan
+	ColorLightGray
+)
+
+const (
+	attrPalette Attribute = 1 << 24
+	attrRGB     Attribute = 1 << 25
+	colorMask             = attrRGB<<1 - 1
+
+	// AttrBold draws the text of a cell in bold
+	AttrBold Attribute = 1 << 32
+)
+
+// PaletteColor returns a color of the 256 color palette, shown in OutputMode256
+func PaletteColor(index uint8) Attribute {
+	return attrPalette | Attribute(index)
+}
+
+// RGBColor returns a true color, shown in OutputModeRGB
+func RGBColor(r, g, b uint8) Attribute {
+	return attrRGB | Attribute(r)<<16 | Attribute(g)<<8 | Attribute(b)
+}
+
+// named returns the named color of the attribute, false if it has a palette or RGB color
+func (a Attribute) named() (Attribute, bool) {
+	color := a & colorMask
+	return color, color <= ColorLightGray
+}
+
+// palette returns the 256 color palette index of the attribute, false if it does not have a palette color
+func (a Attribute) palette() (uint8, bool) {
+	color := a & colorMask
+	return uint8(color), color&attrPalette != 0 && color&attrRGB == 0
+}
+
+// rgb returns the true color of the attribute, false if it does not have an RGB color
+func (a Attribute) rgb() (r, g, b uint8, ok bool) {
+	color := a & colorMask
+	return uint8(color >> 16), uint8(color >> 8), uint8(color), color&attrRGB != 0
+}
+
+// namedRGB holds the xterm shades of the named colors, for screens that can only show RGB colors
+var namedRGB = [...][3]uint8{
+	ColorBlack:        {0, 0, 0},
+	ColorRed:          {205, 0, 0},
+	ColorGreen:        {0, 205, 0},
+	ColorYellow:       {205, 205, 0},
+	ColorBlue:         {0, 0, 238},
+	ColorMagenta:      {205, 0, 205},
+	ColorCyan:         {0, 205, 205},
+	ColorWhite:        {229, 229, 229},
+	ColorDarkGray:     {127, 127, 127},
+	ColorLightRed:     {255, 0, 0},
+	ColorLightGreen:   {0, 255, 0},
+	ColorLightYellow:  {255, 255, 0},
+	ColorLightBlue:    {92, 92, 255},
+	ColorLightMagenta: {255, 0, 255},
+	ColorLightCyan:    {0, 255, 255},
+	ColorLightGray:    {255, 255, 255},
+}
+
+// EventType is the kind of an Event
+type EventType uint8
+
+const (
+	EventKey EventType = iota
+	EventResize
+	EventError
+)
+
+// Key is a key that does not type a character. Control keys have the value of their ASCII control code.
+type Key uint16
+
+const (
+	// KeyRune is a key that types the character in Event.Ch
+	KeyRune      Key = 0x00
+	KeyCtrlC     Key = 0x03
+	KeyTab       Key = 0x09
+	KeyEnter     Key = 0x0D
+	KeyEsc       Key = 0x1B
+	KeySpace     Key = 0x20
+	KeyBackspace Key = 0x7F
+)
+
+const (
+	KeyArrowUp Key = 0xFFFF - iota
+	KeyArrowDown
+	KeyArrowLeft
+	KeyArrowRight
+	KeyHome
+	KeyEnd
+)
+
+// Event is a key press, a resize of the screen or a failure to read input
+type Event struct {
+	Type EventType
+	Key  Key
+	// Ch is the character typed when Key is KeyRune
+	Ch rune
+	// Width and Height are the new size of the screen after a resize
+	Width, Height int
+	Err           error
+}
+
+// ScreenCell is a character drawn on a screen with its colors
+type ScreenCell struct {
+	Ch     rune
+	Fg, Bg Attribute
+}
+
+var blankCell = ScreenCell{Ch: ' '}
+
+// cellBuffer holds the cells of a screen, indexed by row then column
+type cellBuffer struct {
+	width, height int
+	cells         []ScreenCell
+}
+
+// resize keeps the cells that still fit in the new size, filling the rest with blank cells
+func (b *cellBuffer) resize(width, height int) {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	cells := make([]ScreenCell, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			cells[y*width+x] = b.get(x, y)
+		}
+	}
+	b.width, b.height, b.cells = width, height, cells
+}
+
+// get returns the cell at the position, or a blank cell if it is off the screen
+func (b *cellBuffer) get(x, y int) ScreenCell {
+	if x < 0 || y < 0 || x >= b.width || y >= b.height {
+		return blankCell
+	}
+	return b.cells[y*b.width+x]
+}
+
+func (b *cellBuffer) set(x, y int, cell ScreenCell) {
+	if x < 0 || y < 0 || x >= b.width || y >= b.height {
+		return
+	}
+	b.cells[y*b.width+x] = cell
+}
+
+func (b *cellBuffer) fill(cell ScreenCell) {
+	for i := range b.cells {
+		b.cells[i] = cell
+	}
+}
@@ -0,0 +1,140 @@
+package terminalui
+
+import (
+	"strings"
+	"sync"
+)
+
+// simulationEventBuffer is the number of injected events that can wait to be polled
+const simulationEventBuffer = 64
+
+// SimulationScreen is a screen kept in memory, for testing the game or playing it without a terminal. Key
+// presses are injected and the cells shown by the last flush can be inspected.
+type SimulationScreen struct {
+	mu sync.Mutex
+	// back holds the cells drawn since the last flush, front the cells shown by the last flush
+	back, front cellBuffer
+	mode        OutputMode
+	events      chan Event
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+var _ Screen = (*SimulationScreen)(nil)
+
+// NewSimulationScreen builds an empty screen of the given size
+func NewSimulationScreen(width, height int) *SimulationScreen {
+	s := &SimulationScreen{
+		events: make(chan Event, simulationEventBuffer),
+		closed: make(chan struct{}),
+	}
+	s.back.resize(width, height)
+	s.front.resize(width, height)
+	return s
+}
+
+func (s *SimulationScreen) Init() error { return nil }
+
+// Close stops the screen, PollEvent returns an error event once closed
+func (s *SimulationScreen) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+func (s *SimulationScreen) SetOutputMode(mode OutputMode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mode = mode
+}
+
+// OutputMode returns the output mode set by the game
+func (s *SimulationScreen) OutputMode() OutputMode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mode
+}
+
+func (s *SimulationScreen) Size() (width, height int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.back.width, s.back.height
+}
+
+func (s *SimulationScreen) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.back.fill(blankCell)
+}
+
+func (s *SimulationScreen) SetCell(x, y int, ch rune, fg, bg Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.back.set(x, y, ScreenCell{Ch: ch, Fg: fg, Bg: bg})
+}
+
+func (s *SimulationScreen) Flush() error {
+	select {
+	case <-s.closed:
+		return errScreenClosed
+	default:
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copy(s.front.cells, s.back.cells)
+	return nil
+}
+
+func (s *SimulationScreen) PollEvent() Event {
+	select {
+	case ev := <-s.events:
+		return ev
+	case <-s.closed:
+		return Event{Type: EventError, Err: errScreenClosed}
+	}
+}
+
+// InjectKey queues a key press. The character is only used when key is KeyRune.
+func (s *SimulationScreen) InjectKey(key Key, ch rune) {
+	s.events <- Event{Type: EventKey, Key: key, Ch: ch}
+}
+
+// InjectResize resizes the screen and queues the resize event
+func (s *SimulationScreen) InjectResize(width, height int) {
+	s.mu.Lock()
+	s.back.resize(width, height)
+	s.front.resize(width, height)
+	s.mu.Unlock()
+	s.events <- Event{Type: EventResize, Width: width, Height: height}
+}
+
+// Cell returns the cell shown at the position by the last flush
+func (s *SimulationScreen) Cell(x, y int) ScreenCell {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.front.get(x, y)
+}
+
+// Line returns the text shown on a row of the screen by the last flush, without trailing spaces
+func (s *SimulationScreen) Line(y int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.line(y)
+}
+
+// Text returns the text shown on the screen by the last flush, one line per row
+func (s *SimulationScreen) Text() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lines := make([]string, s.front.height)
+	for y := range lines {
+		lines[y] = s.line(y)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s *SimulationScreen) line(y int) string {
+	var sb strings.Builder
+	for x := 0; x < s.front.width; x++ {
+		sb.WriteRune(s.front.get(x, y).Ch)
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
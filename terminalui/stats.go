@@ -0,0 +1,85 @@
+package terminalui
+
+import (
+	"fmt"
+
+	"github.com/brandenc40/2048/stats"
+)
+
+const recentGamesShown = 10
+
+// recordGame adds the current game to the stats store, once per game and only if a move was made. Games
+// autoplay has moved in are not the player's own, so they are never recorded.
+func (u *ui) recordGame() {
+	if u.stats == nil || u.recorded || u.autoplayed || u.gc.GetMoveCount() == 0 {
+		return
+	}
+	u.recorded = true
+	u.statsErr = u.stats.Record(stats.NewGameRecord(u.gc))
+}
+
+func (u *ui) showStats() {
+	if u.stats == nil {
+		return
+	}
+	u.showingStats = true
+	u.drawStats()
+}
+
+func (u *ui) hideStats() {
+	u.showingStats = false
+	u.redraw()
+}
+
+// drawStats draws the statistics screen in place of the game board
+func (u *ui) drawStats() {
+	u.screen.Clear()
+	var (
+		summary = u.stats.Summary()
+		history = u.stats.History()
+		x       = u.layout.borderXStart + 2
+		y       = u.layout.borderYStart
+		winRate float64
+	)
+	if summary.GamesPlayed > 0 {
+		winRate = float64(summary.GamesWon) / float64(summary.GamesPlayed) * 100
+	}
+	lines := []string{
+		"STATISTICS",
+		"",
+		fmt.Sprintf("Games Played:    %d", summary.GamesPlayed),
+		fmt.Sprintf("Games Won:       %d (%.0f%%)", summary.GamesWon, winRate),
+		fmt.Sprintf("Best Score:      %d", summary.BestScore),
+		fmt.Sprintf("Highest Tile:    %d", summary.HighestTile),
+		fmt.Sprintf("Average Score:   %.0f", summary.AverageScore),
+		fmt.Sprintf("Current Streak:  %d", summary.CurrentStreak),
+		fmt.Sprintf("Longest Streak:  %d", summary.LongestStreak),
+		"",
+		"RECENT GAMES",
+		fmt.Sprintf("%-17s %6s %8s %6s %6s", "Finished", "Board", "Score", "Tile", "Moves"),
+	}
+	for i := len(history) - 1; i >= 0 && i >= len(history)-recentGamesShown; i-- {
+		game := history[i]
+		result := ""
+		if game.Won {
+			result = "WON"
+		}
+		lines = append(lines, fmt.Sprintf("%-17s %6s %8d %6d %6d %s",
+			game.Finished.Local().Format("2006-01-02 15:04"),
+			fmt.Sprintf("%dx%d", game.Rows, game.Cols),
+			game.Score, game.HighestTile, game.Moves, result))
+	}
+	if len(history) == 0 {
+		lines = append(lines, "No finished games yet")
+	}
+	if u.statsErr != nil {
+		lines = append(lines, "", "Unable to save statistics: "+u.statsErr.Error())
+	}
+	lines = append(lines, "", "Press any key to return to the game")
+
+	for _, line := range lines {
+		u.print(x, y, u.colorPalate.guide, ColorDefault, line)
+		y++
+	}
+	u.flush()
+}
@@ -0,0 +1,115 @@
+package terminalui
+
+import (
+	"errors"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+var errTcellNotInitialized = errors.New("tcell screen is not initialized")
+
+// tcellScreen draws to the terminal the process runs in with tcell
+type tcellScreen struct {
+	screen tcell.Screen
+}
+
+// NewTcellScreen returns the terminal the process runs in, drawn with tcell. Tcell detects the colors the
+// terminal supports from its terminfo entry, and supports terminals termbox does not, such as the Windows
+// console.
+func NewTcellScreen() Screen {
+	return &tcellScreen{}
+}
+
+func (s *tcellScreen) Init() error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+	if err := screen.Init(); err != nil {
+		return err
+	}
+	s.screen = screen
+	return nil
+}
+
+func (s *tcellScreen) Close() {
+	if s.screen != nil {
+		s.screen.Fini()
+	}
+}
+
+// SetOutputMode has no effect, tcell converts colors to the ones the terminal supports
+func (s *tcellScreen) SetOutputMode(OutputMode) {}
+
+func (s *tcellScreen) Size() (width, height int) { return s.screen.Size() }
+
+func (s *tcellScreen) Clear() { s.screen.Clear() }
+
+func (s *tcellScreen) SetCell(x, y int, ch rune, fg, bg Attribute) {
+	style := tcell.StyleDefault.Foreground(tcellColor(fg)).Background(tcellColor(bg)).Bold(fg&AttrBold != 0)
+	s.screen.SetContent(x, y, ch, nil, style)
+}
+
+func (s *tcellScreen) Flush() error {
+	s.screen.Show()
+	return nil
+}
+
+func (s *tcellScreen) PollEvent() Event {
+	for {
+		switch ev := s.screen.PollEvent().(type) {
+		case nil:
+			// returned once the screen is finalized
+			return Event{Type: EventError, Err: errTcellNotInitialized}
+		case *tcell.EventKey:
+			return tcellKeyEvent(ev)
+		case *tcell.EventResize:
+			width, height := ev.Size()
+			return Event{Type: EventResize, Width: width, Height: height}
+		case *tcell.EventError:
+			return Event{Type: EventError, Err: ev}
+		}
+	}
+}
+
+func tcellKeyEvent(ev *tcell.EventKey) Event {
+	switch ev.Key() {
+	case tcell.KeyRune:
+		if ev.Rune() == ' ' {
+			return Event{Type: EventKey, Key: KeySpace}
+		}
+		return Event{Type: EventKey, Key: KeyRune, Ch: ev.Rune()}
+	case tcell.KeyUp:
+		return Event{Type: EventKey, Key: KeyArrowUp}
+	case tcell.KeyDown:
+		return Event{Type: EventKey, Key: KeyArrowDown}
+	case tcell.KeyLeft:
+		return Event{Type: EventKey, Key: KeyArrowLeft}
+	case tcell.KeyRight:
+		return Event{Type: EventKey, Key: KeyArrowRight}
+	case tcell.KeyHome:
+		return Event{Type: EventKey, Key: KeyHome}
+	case tcell.KeyEnd:
+		return Event{Type: EventKey, Key: KeyEnd}
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		return Event{Type: EventKey, Key: KeyBackspace}
+	default:
+		// tcell reports control keys with their control codes too
+		return Event{Type: EventKey, Key: Key(ev.Key())}
+	}
+}
+
+func tcellColor(a Attribute) tcell.Color {
+	if named, ok := a.named(); ok {
+		if named == ColorDefault {
+			return tcell.ColorDefault
+		}
+		// tcell numbers the named colors from 0 in the same order
+		return tcell.PaletteColor(int(named - ColorBlack))
+	}
+	if index, ok := a.palette(); ok {
+		return tcell.PaletteColor(int(index))
+	}
+	r, g, b, _ := a.rgb()
+	return tcell.NewRGBColor(int32(r), int32(g), int32(b))
+}
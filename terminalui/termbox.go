@@ -0,0 +1,104 @@
+package terminalui
+
+import "github.com/nsf/termbox-go"
+
+// termboxScreen draws to the terminal the process runs in with termbox, the default screen
+type termboxScreen struct {
+	mode OutputMode
+}
+
+// NewTermboxScreen returns the terminal the process runs in, drawn with termbox. Only one termbox screen can
+// be in use at a time.
+func NewTermboxScreen() Screen {
+	return &termboxScreen{mode: OutputModeNormal}
+}
+
+func (s *termboxScreen) Init() error { return termbox.Init() }
+
+func (s *termboxScreen) Close() { termbox.Close() }
+
+func (s *termboxScreen) SetOutputMode(mode OutputMode) {
+	s.mode = mode
+	switch mode {
+	case OutputMode256:
+		termbox.SetOutputMode(termbox.Output256)
+	case OutputModeRGB:
+		termbox.SetOutputMode(termbox.OutputRGB)
+	default:
+		termbox.SetOutputMode(termbox.OutputNormal)
+	}
+}
+
+func (s *termboxScreen) Size() (width, height int) { return termbox.Size() }
+
+func (s *termboxScreen) Clear() {
+	_ = termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+}
+
+func (s *termboxScreen) SetCell(x, y int, ch rune, fg, bg Attribute) {
+	termbox.SetCell(x, y, ch, s.attribute(fg), s.attribute(bg))
+}
+
+func (s *termboxScreen) Flush() error { return termbox.Flush() }
+
+func (s *termboxScreen) PollEvent() Event {
+	ev := termbox.PollEvent()
+	switch ev.Type {
+	case termbox.EventKey:
+		return Event{Type: EventKey, Key: termboxKey(ev), Ch: ev.Ch}
+	case termbox.EventResize:
+		return Event{Type: EventResize, Width: ev.Width, Height: ev.Height}
+	case termbox.EventError:
+		return Event{Type: EventError, Err: ev.Err}
+	default:
+		// mouse and other events are not used by the game
+		return s.PollEvent()
+	}
+}
+
+func termboxKey(ev termbox.Event) Key {
+	if ev.Ch != 0 {
+		return KeyRune
+	}
+	switch ev.Key {
+	case termbox.KeyArrowUp:
+		return KeyArrowUp
+	case termbox.KeyArrowDown:
+		return KeyArrowDown
+	case termbox.KeyArrowLeft:
+		return KeyArrowLeft
+	case termbox.KeyArrowRight:
+		return KeyArrowRight
+	case termbox.KeyHome:
+		return KeyHome
+	case termbox.KeyEnd:
+		return KeyEnd
+	case termbox.KeyBackspace, termbox.KeyBackspace2:
+		return KeyBackspace
+	default:
+		// termbox reports the remaining keys with their control codes too
+		return Key(ev.Key)
+	}
+}
+
+// attribute converts the attribute to termbox's format for the output mode. Colors the output mode cannot
+// show are drawn in the default color.
+func (s *termboxScreen) attribute(a Attribute) termbox.Attribute {
+	var attr termbox.Attribute
+	if named, ok := a.named(); ok {
+		attr = termbox.Attribute(named)
+		if s.mode == OutputModeRGB && named != ColorDefault {
+			rgb := namedRGB[named]
+			attr = termbox.RGBToAttribute(rgb[0], rgb[1], rgb[2])
+		}
+	} else if index, ok := a.palette(); ok && s.mode == OutputMode256 {
+		// termbox offsets palette colors by one so 0 remains the default color
+		attr = termbox.Attribute(index) + 1
+	} else if r, g, b, ok := a.rgb(); ok && s.mode == OutputModeRGB {
+		attr = termbox.RGBToAttribute(r, g, b)
+	}
+	if a&AttrBold != 0 {
+		attr |= termbox.AttrBold
+	}
+	return attr
+}
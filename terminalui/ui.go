@@ -1,46 +1,51 @@
 package terminalui
 
 import (
-	"log"
+	"math/bits"
 	"strconv"
+	"strings"
 
 	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/stats"
 	"github.com/mattn/go-runewidth"
-	"github.com/nsf/termbox-go"
 )
 
-const (
-	// game dimensions
-	width      = 60
-	height     = width / 3
-	x, y       = 2, 1
-	xGap, yGap = 3, 2
-
-	// game cells
-	borderXStart = x
-	borderXEnd   = borderXStart + width
-	borderYStart = y
-	borderYEnd   = borderYStart + height
-	cellsXStart  = borderXStart + 2
-	cellsYStart  = borderYStart + 1
-	cellXGap     = xGap
-	cellYGap     = yGap
-	cellWidth    = (borderXEnd - borderXStart - cellXGap*3) / 4
-	cellHeight   = (borderYEnd - borderYStart - cellYGap*3) / 4
-
-	// score
-	scoreX = borderXStart + 2
-	scoreY = borderYEnd + 2
-)
+func (u *ui) textMsg() []string {
+	if u.replayer != nil {
+		return u.replayMsg()
+	}
+	msg := []string{
+		"HOW TO PLAY: Move the tiles with the keys below.",
+		"Tiles with the same number merge into one when",
+		"they touch. Add them up to reach " + strconv.Itoa(int(u.gc.WinTarget())) + "!",
+		"",
+	}
+	for _, action := range actions {
+		if !u.offers(action) || len(u.keyMap[action]) == 0 {
+			continue
+		}
+		if action == ActionQuit {
+			msg = append(msg, "")
+		}
+		msg = append(msg, u.keyMap.describe(action))
+	}
+	return msg
+}
 
-var textMsg = [...]string{
-	"HOW TO PLAY: Use your arrow keys to move the",
-	"tiles. Tiles with the same number merge into",
-	"one when they touch. Add them up to reach 2048!",
-	"",
-	"Reset the game with 'R' or 'r'",
-	"",
-	"Quit with ESC or CTRL+C",
+// offers returns false for actions that have no effect with the options the game was started with
+func (u *ui) offers(action Action) bool {
+	switch action {
+	case ActionContinue:
+		return u.endless
+	case ActionStats:
+		return u.stats != nil
+	case ActionPause:
+		return u.autoplayer.player != nil
+	case ActionHint:
+		return u.hinter.advisor != nil
+	default:
+		return true
+	}
 }
 
 var logo = [...]string{
@@ -56,102 +61,304 @@ var logo = [...]string{
 
 type ui struct {
 	isOver      bool
+	endless     bool
+	continued   bool
 	gc          game.Controller
 	colorPalate colorPalate
+	layout      layout
+	animator    animator
+	stats       *stats.Store
+	statsErr    error
+	recorded    bool
+	// autoplayed is set once autoplay has moved in the current game, which is then left out of the stats
+	autoplayed   bool
+	showingStats bool
+	autoplayer   autoplayer
+	hinter       hinter
+	replayer     *replayer
+	keyMap       KeyMap
+	keys         map[keyPress]Action
+	screen       Screen
+	outputMode   OutputMode
+	// err is the screen failure that ended the game
+	err error
 }
 
-// Run -
-func Run(gc game.Controller, options ...Option) {
-	u := &ui{
-		gc:          gc,
-		colorPalate: normalPalate(),
+// Run plays the game until the player quits. The game is drawn to the terminal the process runs in unless
+// WithScreen is given, and an error is returned if the screen fails.
+func Run(gc game.Controller, options ...Option) error {
+	u := newUI(gc)
+	if err := u.initialize(options...); err != nil {
+		return err
 	}
-	closeFunc := u.initialize(options...)
-	defer closeFunc()
+	defer u.screen.Close()
 
 	u.drawGameBoard()
 	u.runGameLoop()
+	return u.err
 }
 
-func (u *ui) initialize(options ...Option) (closeFunc func()) {
-	if err := termbox.Init(); err != nil {
-		log.Fatal(err)
+func newUI(gc game.Controller) *ui {
+	return &ui{
+		gc:          gc,
+		colorPalate: normalPalate(),
+		keyMap:      DefaultKeyMap(),
+		animator:    newAnimator(defaultAnimationDuration),
+		screen:      NewTermboxScreen(),
+		outputMode:  OutputModeNormal,
 	}
+}
+
+func (u *ui) initialize(options ...Option) error {
 	for _, option := range options {
 		option.apply(u)
 	}
-	closeFunc = termbox.Close
-	return
+	// WithKeyMap has already checked the bindings
+	u.keys, _ = u.keyMap.bindings()
+	if err := u.screen.Init(); err != nil {
+		return err
+	}
+	u.screen.SetOutputMode(u.outputMode)
+	u.relayout(u.screen.Size())
+	return nil
+}
+
+// flush shows everything drawn since the last flush
+func (u *ui) flush() {
+	if err := u.screen.Flush(); err != nil {
+		u.fail(err)
+	}
+}
+
+// fail ends the game once the screen can no longer be drawn to or read from
+func (u *ui) fail(err error) {
+	if u.err == nil {
+		u.err = err
+	}
 }
 
 func (u *ui) drawGameBoard() {
+	if u.layout.tooSmall {
+		u.drawTooSmall()
+		return
+	}
 	u.drawGameBackground()
 	u.drawGameCells()
 	u.drawScore()
+	u.drawHint()
 	u.drawGuide()
-	if err := termbox.Flush(); err != nil {
-		log.Fatal(err)
-	}
+	u.flush()
 }
 
 func (u *ui) shiftGameController(direction game.Direction) {
 	if u.isOver {
 		return
 	}
-	u.gc.Shift(direction)
+	res := u.gc.Move(direction)
+	if res.Changed {
+		u.cancelHint()
+	}
+	if res.Changed && u.animator.enabled() {
+		u.animator.start(res)
+		u.drawAnimationFrame()
+		return
+	}
+	u.drawShiftResult()
+}
+
+// drawShiftResult draws the board after a shift has completed, ending the game if it was won or lost
+func (u *ui) drawShiftResult() {
+	if u.layout.tooSmall {
+		u.drawTooSmall()
+		return
+	}
+	u.drawGameBackground()
 	u.drawGameCells()
 	u.drawScore()
-	if u.gc.Won() {
-		u.drawOverlayMessage("YOU WIN!")
+	u.drawHint()
+	u.drawGameOver()
+	u.flush()
+}
+
+func (u *ui) drawGameOver() {
+	if u.gc.Won() && !u.continued {
+		if keys := u.keyMap[ActionContinue]; u.endless && len(keys) > 0 {
+			u.drawOverlayMessage("YOU WIN! PRESS '" + strings.ToUpper(keyLabel(keys[0])) + "' TO KEEP GOING")
+		} else {
+			u.drawOverlayMessage("YOU WIN!")
+			u.recordGame()
+		}
 		u.isOver = true
 	}
 	if u.gc.Lost() {
 		u.drawOverlayMessage("NO MORE MOVES, TRY AGAIN")
 		u.isOver = true
+		u.recordGame()
 	}
-	if err := termbox.Flush(); err != nil {
-		log.Fatal(err)
+}
+
+func (u *ui) undo() {
+	if !u.gc.Undo() {
+		return
+	}
+	u.cancelHint()
+	u.isOver = false
+	u.drawGameBoard()
+}
+
+func (u *ui) redo() {
+	if !u.gc.Redo() {
+		return
+	}
+	u.cancelHint()
+	u.drawGameBoard()
+	u.drawGameOver()
+	u.flush()
+}
+
+// continueGame dismisses the win overlay in endless mode so play can go beyond the win target
+func (u *ui) continueGame() {
+	if !u.endless || u.continued || !u.isOver || !u.gc.Won() || u.gc.Lost() {
+		return
 	}
+	u.continued = true
+	u.isOver = false
+	u.drawGameBoard()
 }
+
 func (u *ui) resetGameBoard() {
+	u.recordGame()
+	u.cancelHint()
 	u.gc.Reset()
 	u.isOver = false
+	u.continued = false
+	u.recorded = false
+	u.autoplayed = false
+	u.drawGameBoard()
+}
+
+// redraw draws the whole screen again, such as after the layout changed
+func (u *ui) redraw() {
+	u.screen.Clear()
+	if u.showingStats {
+		u.drawStats()
+		return
+	}
 	u.drawGameBoard()
+	if u.isOver {
+		u.drawGameOver()
+		u.flush()
+	}
 }
 
 func (u *ui) runGameLoop() {
-	for {
-		switch ev := termbox.PollEvent(); ev.Type {
-		case termbox.EventKey:
-			switch ev.Key {
-			case termbox.KeyArrowUp:
-				u.shiftGameController(game.DirectionUp)
-			case termbox.KeyArrowDown:
-				u.shiftGameController(game.DirectionDown)
-			case termbox.KeyArrowRight:
-				u.shiftGameController(game.DirectionRight)
-			case termbox.KeyArrowLeft:
-				u.shiftGameController(game.DirectionLeft)
-			case termbox.KeyCtrlC, termbox.KeyEsc:
+	done := make(chan struct{})
+	defer close(done)
+	events := u.pollEvents(done)
+	u.autoplayer.start()
+	defer u.autoplayer.stop()
+	defer u.cancelHint()
+	for u.err == nil {
+		select {
+		case <-u.autoplayer.tick():
+			u.autoplay()
+		case move := <-u.autoplayer.moves:
+			u.playMove(move)
+		case <-u.animator.tick():
+			if u.animator.advance() {
+				u.drawAnimationFrame()
+			} else {
+				u.drawShiftResult()
+			}
+		case res := <-u.hinter.results:
+			u.showHint(res)
+		case ev := <-events:
+			if quit := u.handleEvent(ev); quit {
 				return
-			default:
-				switch ev.Ch {
-				case 'r', 'R':
-					u.resetGameBoard()
-				}
 			}
-		case termbox.EventResize:
-			u.drawGameBoard()
-		case termbox.EventError:
-			log.Fatal(ev.Err)
 		}
 	}
 }
 
+// handleEvent responds to a terminal event, returning true if the game should quit
+func (u *ui) handleEvent(ev Event) (quit bool) {
+	switch ev.Type {
+	case EventKey:
+		// fast-forward any running animation so the input applies to the final board
+		if u.animator.running() {
+			u.animator.stop()
+			u.drawShiftResult()
+		}
+		action := u.keys[eventKeyPress(ev)]
+		if u.showingStats {
+			u.hideStats()
+			return action == ActionQuit
+		}
+		if u.layout.tooSmall {
+			// the board is not shown, so only quitting is allowed
+			return action == ActionQuit
+		}
+		switch action {
+		case ActionUp:
+			u.shiftGameController(game.DirectionUp)
+		case ActionDown:
+			u.shiftGameController(game.DirectionDown)
+		case ActionRight:
+			u.shiftGameController(game.DirectionRight)
+		case ActionLeft:
+			u.shiftGameController(game.DirectionLeft)
+		case ActionReset:
+			u.resetGameBoard()
+		case ActionUndo:
+			u.undo()
+		case ActionRedo:
+			u.redo()
+		case ActionContinue:
+			u.continueGame()
+		case ActionStats:
+			u.showStats()
+		case ActionPause:
+			u.autoplayer.togglePause()
+		case ActionHint:
+			u.requestHint()
+		case ActionQuit:
+			return true
+		}
+	case EventResize:
+		if u.animator.running() {
+			u.animator.stop()
+		}
+		u.relayout(ev.Width, ev.Height)
+		u.redraw()
+	case EventError:
+		u.fail(ev.Err)
+	}
+	return false
+}
+
+// pollEvents forwards screen events on the returned channel until done is closed
+func (u *ui) pollEvents(done <-chan struct{}) <-chan Event {
+	var (
+		events = make(chan Event)
+		screen = u.screen
+	)
+	go func() {
+		for {
+			ev := screen.PollEvent()
+			select {
+			case events <- ev:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return events
+}
+
 func (u *ui) drawGameBackground() {
-	for x := borderXStart; x <= borderXEnd; x++ {
-		for y := borderYStart; y <= borderYEnd; y++ {
-			termbox.SetCell(x, y, ' ', u.colorPalate.border, u.colorPalate.border)
+	for x := u.layout.borderXStart; x <= u.layout.borderXEnd; x++ {
+		for y := u.layout.borderYStart; y <= u.layout.borderYEnd; y++ {
+			u.screen.SetCell(x, y, ' ', u.colorPalate.border, u.colorPalate.border)
 		}
 	}
 }
@@ -164,62 +371,108 @@ func (u *ui) drawGameCells() {
 	}
 }
 
-func (u *ui) drawGameCell(colIdx, rowIdx int, value uint16) {
+func (u *ui) drawGameCell(colIdx, rowIdx int, value uint32) {
+	xStart, yStart := u.cellOrigin(colIdx, rowIdx)
+	u.drawTile(xStart, yStart, 0, value)
+}
+
+// cellOrigin returns the top left screen position of the cell
+func (u *ui) cellOrigin(colIdx, rowIdx int) (x, y int) {
+	x = u.layout.cellsXStart + colIdx*(u.layout.tile.width+u.layout.tile.xGap)
+	y = u.layout.cellsYStart + rowIdx*(u.layout.tile.height+u.layout.tile.yGap)
+	return
+}
+
+// drawTile draws a tile with its top left corner at the given screen position. The tile is grown on
+// each side by the given number of cells, or shrunk if grow is negative.
+func (u *ui) drawTile(xStart, yStart, grow int, value uint32) {
 	var (
-		bg     = u.colorPalate.empty
-		xStart = cellsXStart + (colIdx * cellWidth) + (colIdx * cellXGap)
-		xEnd   = xStart + cellWidth
-		yStart = cellsYStart + (rowIdx * cellHeight) + (rowIdx * cellYGap)
-		yEnd   = yStart + cellHeight
-		xMid   int
-		yMid   int
+		bg   = u.colorPalate.empty
+		xEnd = xStart + u.layout.tile.width - 1 + grow
+		yEnd = yStart + u.layout.tile.height - 1 + grow
 	)
+	xStart -= grow
+	yStart -= grow
 	if value > 0 {
-		bg = u.colorPalate.values[value]
-		xMid = (xStart + xEnd) / 2
-		yMid = (yStart + yEnd) / 2
-		if value > 100 {
-			xMid -= 1
-		}
+		bg = u.colorPalate.valueColor(value)
 	}
 	for x := xStart; x <= xEnd; x++ {
 		for y := yStart; y <= yEnd; y++ {
-			termbox.SetCell(x, y, ' ', termbox.ColorWhite, bg)
+			u.screen.SetCell(x, y, ' ', ColorWhite, bg)
 		}
 	}
-	if value > 0 {
-		val := strconv.FormatUint(uint64(value), 10)
-		tbPrint(xMid, yMid, u.colorPalate.valueText, bg, val)
+	if value > 0 && yStart <= yEnd {
+		if u.layout.bigDigits && u.drawBigValue(xStart, yStart, xEnd, yEnd, value, bg) {
+			return
+		}
+		val := formatValue(value, xEnd-xStart+1)
+		u.print(xStart+(xEnd-xStart+1-len(val))/2, (yStart+yEnd)/2, u.colorPalate.valueText, bg, val)
+	}
+}
+
+// formatValue formats a cell value to fit within the given width, falling back to a power of two
+// and then an abbreviated number for values too wide to print in full
+func formatValue(value uint32, width int) string {
+	val := strconv.FormatUint(uint64(value), 10)
+	if len(val) <= width {
+		return val
+	}
+	if exp := "2^" + strconv.Itoa(bits.TrailingZeros32(value)); len(exp) <= width {
+		return exp
+	}
+	switch {
+	case value >= 1<<30:
+		return strconv.FormatUint(uint64(value>>30), 10) + "G"
+	case value >= 1<<20:
+		return strconv.FormatUint(uint64(value>>20), 10) + "M"
+	default:
+		return strconv.FormatUint(uint64(value>>10), 10) + "K"
 	}
 }
 
 func (u *ui) drawScore() {
-	msg := "Current Score: " + strconv.Itoa((int)(u.gc.GetScore()))
-	tbPrint(scoreX, scoreY, u.colorPalate.score, termbox.ColorDefault, msg)
+	// clear the line first, the score can decrease after an undo
+	u.clearInfoLine(u.layout.scoreY)
+	msg := "Current Score: " + strconv.FormatUint(u.gc.GetScore(), 10)
+	if u.stats != nil {
+		best := u.stats.Summary().BestScore
+		if score := u.gc.GetScore(); score > best {
+			best = score
+		}
+		msg += "   Best: " + strconv.FormatUint(best, 10)
+	}
+	u.print(u.layout.scoreX, u.layout.scoreY, u.colorPalate.score, ColorDefault, msg)
+	if remaining := u.gc.UndosRemaining(); remaining >= 0 {
+		msg = "Undos Left: " + strconv.Itoa(remaining)
+		u.print(u.layout.infoXEnd-len(msg)-1, u.layout.scoreY, u.colorPalate.score, ColorDefault, msg)
+	}
 }
 
 func (u *ui) drawGuide() {
-	y := borderYStart
-	x := borderXEnd + 2
-	for _, line := range logo {
-		y++
-		tbPrint(x, y, u.colorPalate.guide, termbox.ColorDefault, line)
+	if u.layout.guide == guideHidden {
+		return
 	}
-	for _, line := range textMsg {
-		y++
-		tbPrint(x, y, u.colorPalate.guide, termbox.ColorDefault, line)
+	lines := u.textMsg()
+	if u.layout.showLogo {
+		lines = withLogo(lines)
+	}
+	for i, line := range lines {
+		u.print(u.layout.guideX, u.layout.guideY+i, u.colorPalate.guide, ColorDefault, line)
 	}
 }
 
 func (u *ui) drawOverlayMessage(message string) {
-	x := borderXStart + (width-len(message))/2
-	y := borderYStart + height/2
-	tbPrint(x, y, u.colorPalate.overlayText, u.colorPalate.overlayBg, message)
+	if u.layout.tooSmall {
+		return
+	}
+	x := u.layout.borderXStart + (u.layout.width-len(message))/2
+	y := u.layout.borderYStart + u.layout.height/2
+	u.print(x, y, u.colorPalate.overlayText, u.colorPalate.overlayBg, message)
 }
 
-func tbPrint(x, y int, fg, bg termbox.Attribute, msg string) {
+func (u *ui) print(x, y int, fg, bg Attribute, msg string) {
 	for _, c := range msg {
-		termbox.SetCell(x, y, c, fg, bg)
+		u.screen.SetCell(x, y, c, fg, bg)
 		x += runewidth.RuneWidth(c)
 	}
 }
@@ -0,0 +1,298 @@
+package terminalui
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/stats"
+)
+
+// finishedGame reports the game as won or lost whatever its board
+type finishedGame struct {
+	game.Controller
+	won, lost bool
+}
+
+func (g finishedGame) Won() bool  { return g.won }
+func (g finishedGame) Lost() bool { return g.lost }
+
+func newTestUI(t *testing.T, gc game.Controller, options ...Option) (*ui, *SimulationScreen) {
+	t.Helper()
+	screen := NewSimulationScreen(120, 40)
+	u := newUI(gc)
+	if err := u.initialize(append([]Option{WithScreen(screen)}, options...)...); err != nil {
+		t.Fatal(err)
+	}
+	return u, screen
+}
+
+func TestUI_drawGameBoard(t *testing.T) {
+	gc := game.NewController(game.WithSeed(1))
+	u, screen := newTestUI(t, gc)
+	u.drawGameBoard()
+
+	equal(t, OutputModeNormal, screen.OutputMode())
+	equal(t, "    Current Score: 0", screen.Line(u.layout.scoreY))
+	equal(t, u.colorPalate.border, screen.Cell(u.layout.borderXStart, u.layout.borderYStart).Bg)
+	equal(t, u.colorPalate.border, screen.Cell(u.layout.borderXEnd, u.layout.borderYEnd).Bg)
+	for rowIdx, row := range gc.GetCells() {
+		for colIdx, value := range row {
+			x, y := u.cellOrigin(colIdx, rowIdx)
+			if value == 0 {
+				equal(t, u.colorPalate.empty, screen.Cell(x+u.layout.tile.width/2, y+1).Bg)
+				continue
+			}
+			text := strconv.Itoa(int(value))
+			x += (u.layout.tile.width - len(text)) / 2
+			cell := screen.Cell(x, y+1)
+			equal(t, rune(text[0]), cell.Ch)
+			equal(t, u.colorPalate.valueColor(value), cell.Bg)
+			equal(t, u.colorPalate.valueText, cell.Fg)
+		}
+	}
+	text := screen.Text()
+	for _, line := range u.textMsg() {
+		equal(t, true, strings.Contains(text, line))
+	}
+	equal(t, false, strings.Contains(text, "statistics"))
+}
+
+func TestUI_outputMode(t *testing.T) {
+	gc := game.NewController(game.WithSeed(1))
+	u, screen := newTestUI(t, gc, WithOutputMode(OutputModeRGB))
+	u.drawGameBoard()
+	equal(t, OutputModeRGB, screen.OutputMode())
+	_, _, _, ok := screen.Cell(u.layout.borderXStart, u.layout.borderYStart).Bg.rgb()
+	equal(t, true, ok)
+}
+
+func TestUI_drawGameOver(t *testing.T) {
+	overlayLine := func(u *ui, screen *SimulationScreen) string {
+		return screen.Line(u.layout.borderYStart + u.layout.height/2)
+	}
+	gc := game.NewController(game.WithSeed(1))
+
+	u, screen := newTestUI(t, finishedGame{Controller: gc, lost: true})
+	u.drawShiftResult()
+	equal(t, true, strings.Contains(overlayLine(u, screen), "NO MORE MOVES, TRY AGAIN"))
+	equal(t, true, u.isOver)
+
+	u, screen = newTestUI(t, finishedGame{Controller: gc, won: true}, WithEndless())
+	u.drawShiftResult()
+	equal(t, true, strings.Contains(overlayLine(u, screen), "YOU WIN! PRESS 'C' TO KEEP GOING"))
+	u.continueGame()
+	equal(t, false, u.isOver)
+	equal(t, false, strings.Contains(overlayLine(u, screen), "YOU WIN"))
+}
+
+func TestRun(t *testing.T) {
+	var (
+		gc        = game.NewController(game.WithSeed(3))
+		reference = game.NewController(game.WithSeed(3))
+		screen    = NewSimulationScreen(120, 40)
+	)
+	screen.InjectKey(KeyArrowLeft, 0)
+	screen.InjectKey(KeyArrowUp, 0)
+	screen.InjectKey(KeyRune, 'u')
+	screen.InjectKey(KeyArrowDown, 0)
+	screen.InjectKey(KeyCtrlC, 0)
+	err := Run(gc, WithScreen(screen), WithoutAnimations(), WithStats(stats.NewStore()))
+	equal(t, nil, err)
+
+	reference.Shift(game.DirectionLeft)
+	reference.Shift(game.DirectionUp)
+	reference.Undo()
+	reference.Shift(game.DirectionDown)
+	equal(t, reference.GetCells(), gc.GetCells())
+	equal(t, reference.GetScore(), gc.GetScore())
+	score := strconv.FormatUint(gc.GetScore(), 10)
+	equal(t, true, regexp.MustCompile(`Current Score: `+score+`   Best: `+score+`\b`).MatchString(screen.Text()))
+}
+
+// blockingPlayer signals when it is asked for a move, then chooses left once it is released
+type blockingPlayer struct {
+	asked, release chan struct{}
+}
+
+func (p blockingPlayer) NextMove(game.Cells) (game.Direction, bool) {
+	p.asked <- struct{}{}
+	<-p.release
+	return game.DirectionLeft, true
+}
+
+func TestRun_autoplayInBackground(t *testing.T) {
+	var (
+		player = blockingPlayer{asked: make(chan struct{}), release: make(chan struct{})}
+		screen = NewSimulationScreen(120, 40)
+		result = make(chan error)
+	)
+	defer close(player.release)
+	go func() {
+		result <- Run(game.NewController(), WithScreen(screen), WithAutoplay(player, time.Millisecond))
+	}()
+	<-player.asked
+	// keys are still handled while the player is choosing
+	screen.InjectKey(KeyCtrlC, 0)
+	equal(t, nil, <-result)
+}
+
+func TestUI_playMove(t *testing.T) {
+	var (
+		gc        = game.NewController(game.WithSeed(3))
+		reference = game.NewController(game.WithSeed(3))
+		u, _      = newTestUI(t, gc, WithoutAnimations(), WithAutoplay(blockingPlayer{}, time.Second))
+		stale     = gc.GetCells()
+	)
+	u.shiftGameController(game.DirectionLeft)
+	reference.Shift(game.DirectionLeft)
+	// the board changed while the player was choosing, so its move is dropped
+	u.playMove(autoplayMove{cells: stale, direction: game.DirectionUp, ok: true})
+	equal(t, reference.GetCells(), gc.GetCells())
+
+	u.playMove(autoplayMove{cells: gc.GetCells(), direction: game.DirectionUp, ok: true})
+	reference.Shift(game.DirectionUp)
+	equal(t, reference.GetCells(), gc.GetCells())
+}
+
+func TestUI_stats(t *testing.T) {
+	gc := game.NewController(game.WithSeed(3))
+	u, screen := newTestUI(t, gc, WithStats(stats.NewStore()))
+	gc.Shift(game.DirectionLeft)
+	u.resetGameBoard()
+	u.showStats()
+	text := screen.Text()
+	equal(t, true, strings.Contains(text, "STATISTICS"))
+	equal(t, true, strings.Contains(text, "Games Played:    1"))
+	equal(t, false, strings.Contains(text, "Current Score"))
+
+	u.hideStats()
+	equal(t, false, strings.Contains(screen.Text(), "STATISTICS"))
+}
+
+func TestUI_statsAutoplay(t *testing.T) {
+	var (
+		gc    = game.NewController(game.WithSeed(3))
+		store = stats.NewStore()
+		u, _  = newTestUI(t, gc, WithoutAnimations(), WithStats(store), WithAutoplay(blockingPlayer{}, time.Second))
+	)
+	u.playMove(autoplayMove{cells: gc.GetCells(), direction: game.DirectionLeft, ok: true})
+	u.resetGameBoard()
+	// the game autoplay moved in is left out, the next one is the player's own
+	equal(t, 0, len(store.History()))
+	u.shiftGameController(game.DirectionLeft)
+	u.resetGameBoard()
+	equal(t, 1, len(store.History()))
+}
+
+func TestUI_statsQuit(t *testing.T) {
+	keys := DefaultKeyMap()
+	keys[ActionQuit] = []string{"q"}
+	u, _ := newTestUI(t, game.NewController(), WithStats(stats.NewStore()), WithKeyMap(keys))
+	u.showStats()
+	// CTRL+C is no longer bound to quit, so it only closes the statistics
+	equal(t, false, u.handleEvent(Event{Type: EventKey, Key: KeyCtrlC}))
+	equal(t, false, u.showingStats)
+	u.showStats()
+	equal(t, true, u.handleEvent(Event{Type: EventKey, Key: KeyRune, Ch: 'q'}))
+}
+
+func TestUI_resize(t *testing.T) {
+	var (
+		gc        = game.NewController(game.WithSeed(1))
+		reference = game.NewController(game.WithSeed(1))
+		screen    = NewSimulationScreen(20, 10)
+		u         = newUI(gc)
+	)
+	if err := u.initialize(WithScreen(screen)); err != nil {
+		t.Fatal(err)
+	}
+	u.drawGameBoard()
+	equal(t, true, u.layout.tooSmall)
+	// moves are ignored while the board does not fit
+	equal(t, false, u.handleEvent(Event{Type: EventKey, Key: KeyArrowLeft}))
+	equal(t, reference.GetCells(), gc.GetCells())
+
+	screen.InjectResize(120, 40)
+	u.handleEvent(screen.PollEvent())
+	equal(t, false, u.layout.tooSmall)
+	text := screen.Text()
+	equal(t, true, strings.Contains(text, "Current Score: 0"))
+	equal(t, false, strings.Contains(text, "Terminal too small"))
+}
+
+func TestRun_tooSmall(t *testing.T) {
+	screen := NewSimulationScreen(40, 10)
+	screen.InjectKey(KeyEsc, 0)
+	equal(t, nil, Run(game.NewController(), WithScreen(screen)))
+	equal(t, "Terminal too small", strings.TrimSpace(screen.Line(4)))
+	equal(t, "Resize to at least 31x13", strings.TrimSpace(screen.Line(5)))
+}
+
+func TestNewLayout(t *testing.T) {
+	guide := newUI(game.NewController()).textMsg()
+	tests := []struct {
+		name          string
+		width, height int
+		tile          tileSize
+		guide         guidePlacement
+		showLogo      bool
+	}{
+		{"default", 120, 40, tileSizes[2], guideBeside, true},
+		{"large", 200, 50, tileSizes[0], guideBeside, true},
+		{"narrow", 70, 60, tileSizes[2], guideBelow, true},
+		{"classic terminal", 80, 24, tileSizes[4], guideBeside, false},
+		{"tiny", 40, 14, tileSizes[5], guideHidden, false},
+		{"too small", 20, 10, tileSizes[5], guideHidden, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLayout(4, 4, tt.width, tt.height, guide)
+			equal(t, tt.tile, l.tile)
+			equal(t, tt.guide, l.guide)
+			equal(t, tt.showLogo, l.showLogo)
+			equal(t, tt.name == "too small", l.tooSmall)
+			if !l.tooSmall {
+				equal(t, true, l.borderXEnd < tt.width && l.scoreY+1 < tt.height)
+			}
+		})
+	}
+}
+
+func TestUI_bigDigits(t *testing.T) {
+	gc := game.NewController(game.WithSeed(1))
+	screen := NewSimulationScreen(200, 50)
+	u := newUI(gc)
+	if err := u.initialize(WithScreen(screen)); err != nil {
+		t.Fatal(err)
+	}
+	u.drawGameBoard()
+	equal(t, true, u.layout.bigDigits)
+	text := screen.Text()
+	for _, row := range gc.GetCells() {
+		for _, value := range row {
+			if value > 0 {
+				equal(t, true, strings.Contains(text, bigDigits[value][0]))
+			}
+		}
+	}
+}
+
+func TestRun_screenClosed(t *testing.T) {
+	screen := NewSimulationScreen(120, 40)
+	screen.Close()
+	err := Run(game.NewController(), WithScreen(screen))
+	equal(t, true, errors.Is(err, errScreenClosed))
+}
+
+func equal(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
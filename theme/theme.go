@@ -0,0 +1,66 @@
+// Package theme holds the colours shared by the game frontends, so the terminal and browser boards look
+// the same
+package theme
+
+import "fmt"
+
+// RGB is a 24-bit colour
+type RGB struct {
+	R, G, B uint8
+}
+
+// Hex returns the colour in CSS hex notation, such as "#ebe4db"
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Tiles maps cell values to their background colour
+var Tiles = map[uint32]RGB{
+	2:      {235, 228, 219},
+	4:      {234, 225, 204},
+	8:      {233, 180, 129},
+	16:     {232, 154, 108},
+	32:     {231, 132, 103},
+	64:     {229, 105, 72},
+	128:    {232, 209, 128},
+	256:    {232, 205, 114},
+	512:    {231, 202, 101},
+	1024:   {230, 197, 90},
+	2048:   {230, 196, 79},
+	4096:   {236, 140, 185},
+	8192:   {205, 135, 230},
+	16384:  {150, 160, 235},
+	32768:  {115, 195, 230},
+	65536:  {120, 215, 165},
+	131072: {195, 225, 120},
+}
+
+var (
+	// TileText is the colour of the numbers on the tiles
+	TileText = RGB{32, 32, 31}
+	// Empty is the background of an empty cell
+	Empty = RGB{202, 193, 181}
+	// Border is the background of the board around the cells
+	Border = RGB{185, 173, 161}
+	// Score is the colour of the score
+	Score = RGB{71, 155, 95}
+	// Text is the colour of the guide and other messages
+	Text = RGB{250, 248, 240}
+	// OverlayBg is the background of messages drawn over the board
+	OverlayBg = RGB{32, 32, 31}
+)
+
+// TileColor returns the background colour for a cell value. Values beyond the largest colour in Tiles
+// share its colour.
+func TileColor(value uint32) RGB {
+	if color, ok := Tiles[value]; ok {
+		return color
+	}
+	var largest uint32
+	for v := range Tiles {
+		if v > largest && v < value {
+			largest = v
+		}
+	}
+	return Tiles[largest]
+}
@@ -0,0 +1,21 @@
+package web
+
+import "github.com/brandenc40/2048/game"
+
+// Option configures a Handler built by New
+type Option interface {
+	apply(h *Handler)
+}
+
+// WithGameOptions sets the options used to build the game for each connection
+func WithGameOptions(options ...game.Option) Option {
+	return gameOptionsOption{options: options}
+}
+
+type gameOptionsOption struct {
+	options []game.Option
+}
+
+func (o gameOptionsOption) apply(h *Handler) {
+	h.gameOptions = append(h.gameOptions, o.options...)
+}
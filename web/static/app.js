@@ -0,0 +1,130 @@
+"use strict";
+
+(function () {
+  const board = document.getElementById("board");
+  const overlay = document.getElementById("overlay");
+  const score = document.getElementById("score");
+  const status = document.getElementById("status");
+  const keys = {
+    ArrowLeft: "left", ArrowUp: "up", ArrowRight: "right", ArrowDown: "down",
+    a: "left", w: "up", d: "right", s: "down",
+  };
+  let socket;
+  let previous = null;
+  let continued = false;
+
+  function connect() {
+    const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+    socket = new WebSocket(scheme + "//" + location.host + "/ws");
+    socket.onopen = () => { status.textContent = ""; };
+    socket.onmessage = (event) => render(JSON.parse(event.data));
+    socket.onclose = () => {
+      status.textContent = "Disconnected, reconnecting with a new game...";
+      previous = null;
+      setTimeout(connect, 2000);
+    };
+  }
+
+  function send(command) {
+    if (socket && socket.readyState === WebSocket.OPEN) {
+      socket.send(JSON.stringify(command));
+    }
+  }
+
+  function move(direction) {
+    send({ action: "move", direction: direction });
+  }
+
+  function render(state) {
+    if (state.error) {
+      status.textContent = state.error;
+    }
+    if (!state.cells) {
+      return;
+    }
+    const rows = state.cells.length;
+    const cols = state.cells[0].length;
+    board.style.gridTemplateColumns = "repeat(" + cols + ", 1fr)";
+    board.querySelectorAll(".cell").forEach((cell) => cell.remove());
+    state.cells.forEach((row, r) => {
+      row.forEach((value, c) => {
+        const cell = document.createElement("div");
+        cell.className = "cell";
+        if (value > 0) {
+          cell.textContent = value;
+          // theme.css colours the tile by its value, falling back to tile-super for values without a colour
+          cell.classList.add("tile-super", "tile-" + value);
+          if (previous && previous.length === rows && previous[r][c] === 0 && state.changed) {
+            cell.classList.add("spawned");
+          }
+        }
+        board.insertBefore(cell, overlay);
+      });
+    });
+    previous = state.cells;
+    score.textContent = state.score;
+
+    if (state.moves === 0) {
+      continued = false;
+    }
+    if (state.lost) {
+      showOverlay("No more moves, try again");
+    } else if (state.won && !continued) {
+      showOverlay("You win! Click to keep going");
+    } else {
+      overlay.hidden = true;
+    }
+  }
+
+  function showOverlay(message) {
+    overlay.textContent = message;
+    overlay.hidden = false;
+  }
+
+  overlay.addEventListener("click", () => {
+    continued = true;
+    overlay.hidden = true;
+  });
+
+  document.addEventListener("keydown", (event) => {
+    const direction = keys[event.key];
+    if (direction) {
+      event.preventDefault();
+      move(direction);
+    } else if (event.key === "u") {
+      send({ action: "undo" });
+    } else if (event.key === "y") {
+      send({ action: "redo" });
+    } else if (event.key === "r") {
+      send({ action: "reset" });
+    }
+  });
+
+  let touchStart = null;
+  board.addEventListener("touchstart", (event) => {
+    touchStart = event.touches[0];
+  }, { passive: true });
+  board.addEventListener("touchend", (event) => {
+    if (!touchStart) {
+      return;
+    }
+    const end = event.changedTouches[0];
+    const dx = end.clientX - touchStart.clientX;
+    const dy = end.clientY - touchStart.clientY;
+    touchStart = null;
+    if (Math.max(Math.abs(dx), Math.abs(dy)) < 30) {
+      return;
+    }
+    if (Math.abs(dx) > Math.abs(dy)) {
+      move(dx > 0 ? "right" : "left");
+    } else {
+      move(dy > 0 ? "down" : "up");
+    }
+  });
+
+  document.getElementById("undo").addEventListener("click", () => send({ action: "undo" }));
+  document.getElementById("redo").addEventListener("click", () => send({ action: "redo" }));
+  document.getElementById("reset").addEventListener("click", () => send({ action: "reset" }));
+
+  connect();
+})();
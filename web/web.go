@@ -0,0 +1,163 @@
+// Package web serves a browser version of the game. The page is embedded in the binary and plays over a
+// WebSocket, with each connection backed by its own game.Controller.
+//
+// The WebSocket at /ws sends a headless.State as JSON when the connection opens and after every command.
+// Commands are headless.Command JSON objects such as {"action":"move","direction":"left"}.
+package web
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/headless"
+	"github.com/brandenc40/2048/theme"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	maxMessageBytes = 1 << 10
+	pongWait        = 60 * time.Second
+	pingInterval    = pongWait / 2
+	writeWait       = 10 * time.Second
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the page, its theme and the WebSocket
+type Handler struct {
+	mux         *http.ServeMux
+	upgrader    websocket.Upgrader
+	gameOptions []game.Option
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// New builds the handler for the browser game
+func New(options ...Option) *Handler {
+	h := &Handler{
+		mux: http.NewServeMux(),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  maxMessageBytes,
+			WriteBufferSize: maxMessageBytes,
+		},
+	}
+	for _, option := range options {
+		option.apply(h)
+	}
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	h.mux.Handle("/", http.FileServer(http.FS(files)))
+	h.mux.HandleFunc("/theme.css", serveTheme)
+	h.mux.HandleFunc("/ws", h.serveWebSocket)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// serveWebSocket plays a new game for the lifetime of the connection
+func (h *Handler) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already replied with an error
+		return
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go keepAlive(conn, done)
+
+	conn.SetReadLimit(maxMessageBytes)
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	gc := game.NewController(h.gameOptions...)
+	if err := writeState(conn, headless.NewState(gc)); err != nil {
+		return
+	}
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var cmd headless.Command
+		state := headless.NewState(gc)
+		if err := json.Unmarshal(msg, &cmd); err != nil {
+			state.Error = "invalid command: " + err.Error()
+		} else if state, err = headless.Apply(gc, cmd); err != nil {
+			state = headless.NewState(gc)
+			state.Error = err.Error()
+		}
+		if err := writeState(conn, state); err != nil {
+			return
+		}
+	}
+}
+
+func writeState(conn *websocket.Conn, state headless.State) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteJSON(state)
+}
+
+// keepAlive pings the browser until done is closed so dead connections time out
+func keepAlive(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// serveTheme writes the colours of the theme package as CSS, so the page matches the terminal UI
+func serveTheme(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	fmt.Fprint(w, ThemeCSS())
+}
+
+// ThemeCSS returns the theme colours as CSS custom properties and a class per tile value, such as .tile-2048.
+// Tiles are given both .tile-super and the class of their value, so tiles without a colour of their own fall
+// back to the colour of the largest tile in the theme.
+func ThemeCSS() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, ":root {\n")
+	fmt.Fprintf(&sb, "  --tile-text: %s;\n", theme.TileText.Hex())
+	fmt.Fprintf(&sb, "  --empty: %s;\n", theme.Empty.Hex())
+	fmt.Fprintf(&sb, "  --border: %s;\n", theme.Border.Hex())
+	fmt.Fprintf(&sb, "  --score: %s;\n", theme.Score.Hex())
+	fmt.Fprintf(&sb, "  --text: %s;\n", theme.Text.Hex())
+	fmt.Fprintf(&sb, "  --overlay-bg: %s;\n", theme.OverlayBg.Hex())
+	fmt.Fprintf(&sb, "}\n")
+
+	values := make([]uint32, 0, len(theme.Tiles))
+	for value := range theme.Tiles {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	// written before the tile classes, which override it for every value in the theme
+	fmt.Fprintf(&sb, ".tile-super { background: %s; }\n", theme.Tiles[values[len(values)-1]].Hex())
+	for _, value := range values {
+		fmt.Fprintf(&sb, ".tile-%d { background: %s; }\n", value, theme.Tiles[value].Hex())
+	}
+	return sb.String()
+}
@@ -0,0 +1,116 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/brandenc40/2048/game"
+	"github.com/brandenc40/2048/headless"
+	"github.com/gorilla/websocket"
+)
+
+func dial(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func send(t *testing.T, conn *websocket.Conn, msg string) headless.State {
+	t.Helper()
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+		t.Fatal(err)
+	}
+	return receive(t, conn)
+}
+
+func receive(t *testing.T, conn *websocket.Conn) headless.State {
+	t.Helper()
+	var state headless.State
+	if err := conn.ReadJSON(&state); err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func TestHandler_webSocket(t *testing.T) {
+	srv := httptest.NewServer(New(WithGameOptions(game.WithSeed(3), game.WithSize(3, 3))))
+	defer srv.Close()
+	conn := dial(t, srv)
+	defer conn.Close()
+	reference := game.NewController(game.WithSeed(3), game.WithSize(3, 3))
+
+	state := receive(t, conn)
+	equal(t, reference.GetCells(), state.Cells)
+
+	state = send(t, conn, `{"action":"move","direction":"left"}`)
+	res := reference.Move(game.DirectionLeft)
+	equal(t, reference.GetCells(), state.Cells)
+	equal(t, res.Changed, state.Changed)
+	equal(t, reference.GetScore(), state.Score)
+
+	state = send(t, conn, `{"action":"undo"}`)
+	reference.Undo()
+	equal(t, reference.GetCells(), state.Cells)
+
+	state = send(t, conn, `{"action":"move","direction":"sideways"}`)
+	equal(t, true, state.Error != "")
+	equal(t, reference.GetCells(), state.Cells)
+
+	state = send(t, conn, `not json`)
+	equal(t, true, strings.HasPrefix(state.Error, "invalid command"))
+	equal(t, reference.GetCells(), state.Cells)
+
+	// each connection plays its own game
+	other := dial(t, srv)
+	defer other.Close()
+	equal(t, game.NewController(game.WithSeed(3), game.WithSize(3, 3)).GetCells(), receive(t, other).Cells)
+}
+
+func TestHandler_static(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+	for path, contentType := range map[string]string{
+		"/":          "text/html",
+		"/app.js":    "javascript",
+		"/style.css": "text/css",
+		"/theme.css": "text/css",
+	} {
+		resp, err := srv.Client().Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		equal(t, http.StatusOK, resp.StatusCode)
+		if !strings.Contains(resp.Header.Get("Content-Type"), contentType) {
+			t.Errorf("%s: expected content type %s, got %s", path, contentType, resp.Header.Get("Content-Type"))
+		}
+		equal(t, true, len(body) > 0)
+	}
+}
+
+func TestThemeCSS(t *testing.T) {
+	css := ThemeCSS()
+	for _, want := range []string{"--tile-text: #", ".tile-2 {", ".tile-2048 {", ".tile-131072 {", ".tile-super {"} {
+		if !strings.Contains(css, want) {
+			t.Errorf("expected the theme to contain %q", want)
+		}
+	}
+	equal(t, true, strings.Index(css, ".tile-4 {") < strings.Index(css, ".tile-1024 {"))
+	// the fallback comes first so the colour of each tile's own value wins
+	equal(t, true, strings.Index(css, ".tile-super {") < strings.Index(css, ".tile-2 {"))
+}
+
+func equal(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}